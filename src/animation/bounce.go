@@ -0,0 +1,32 @@
+package animation
+
+import (
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+// Bounce moves a point under gravity, bouncing it off a floor and losing
+// some of its speed on every bounce.
+type Bounce struct {
+	Pos         util.Pos[float64]
+	Velocity    util.Pos[float64]
+	Gravity     float64
+	Floor       float64
+	Restitution float64
+}
+
+func (b *Bounce) Update() {
+	// Accelerate downwards and move
+	b.Velocity.Y += b.Gravity
+	b.Pos = b.Pos.TranslatePos(b.Velocity)
+
+	// Bounce back up off the floor
+	if b.Pos.Y > b.Floor {
+		b.Pos.Y = b.Floor
+		b.Velocity.Y = -b.Velocity.Y * b.Restitution
+	}
+}
+
+// OutsideX reports whether the point has left the horizontal range [minX, maxX].
+func (b *Bounce) OutsideX(minX, maxX float64) bool {
+	return b.Pos.X < minX || b.Pos.X > maxX
+}
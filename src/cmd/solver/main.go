@@ -0,0 +1,54 @@
+// Command solver reports whether Klondike deals can be won.
+//
+//	solver --seed 1234 --draw 3 --moves
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/solver"
+)
+
+func main() {
+	// Parse the command-line options
+	opts := model.DefaultOptions
+	solverOpts := solver.DefaultOptions
+	seed := flag.Uint64("seed", 0, "seed of the first deal to solve")
+	count := flag.Int("count", 1, "number of consecutive seeds to solve")
+	flag.IntVar(&opts.DrawCount, "draw", opts.DrawCount, "number of cards turned from the stock at once (1 or 3)")
+	flag.IntVar(&opts.Passes, "passes", opts.Passes, "number of passes allowed through the stock, 0 for no limit")
+	flag.BoolVar(&solverOpts.Thoughtful, "thoughtful", solverOpts.Thoughtful, "let the solver see face-down cards")
+	flag.IntVar(&solverOpts.MaxNodes, "nodes", solverOpts.MaxNodes, "maximum number of positions to search per deal, 0 for no limit")
+	flag.DurationVar(&solverOpts.MaxDuration, "timeout", solverOpts.MaxDuration, "maximum time to search per deal, 0 for no limit")
+	printMoves := flag.Bool("moves", false, "print the winning line of play")
+	flag.Parse()
+	if err := opts.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Solve each deal in turn, tallying the results
+	totals := map[solver.Status]int{}
+	for i := 0; i < *count; i++ {
+		dealSeed := *seed + uint64(i)
+		result := solver.Solve(model.NewGame(dealSeed, opts), solverOpts, nil)
+		totals[result.Status]++
+		fmt.Printf("seed %d: %s (%d nodes, %s)\n", dealSeed, result.Status, result.Nodes, result.Duration)
+		if *printMoves {
+			for j, move := range result.Moves {
+				fmt.Printf("  %3d. %s\n", j+1, move)
+			}
+		}
+	}
+	if *count > 1 {
+		fmt.Printf("%d solvable, %d unsolvable, %d unknown\n", totals[solver.Solvable], totals[solver.Unsolvable], totals[solver.Unknown])
+	}
+
+	// Exit with a failure status if a single deal could not be shown to be winnable
+	if *count == 1 && totals[solver.Solvable] == 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,91 @@
+package deals
+
+import (
+	"log"
+	"runtime"
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/solver"
+)
+
+const DEFAULT_POOL_SIZE = 4
+
+// VerifyOptions bound the search spent on each candidate deal. Deals that
+// can't be solved quickly are simply skipped in favour of the next one.
+var VerifyOptions = solver.Options{
+	Thoughtful:  true,
+	MaxNodes:    200_000,
+	MaxDuration: 2 * time.Second,
+}
+
+// Deal is a deal that the solver has shown to be winnable.
+type Deal struct {
+	Seed  uint64
+	Moves int
+}
+
+// Pool searches for winnable deals in the background so that one is always
+// ready when a new game starts.
+type Pool struct {
+	opts  model.Options
+	deals chan Deal
+	done  chan struct{}
+}
+
+func NewPool(opts model.Options, size int) *Pool {
+	p := &Pool{
+		opts:  opts,
+		deals: make(chan Deal, size),
+		done:  make(chan struct{}),
+	}
+
+	// Leave a core free for the game itself
+	workers := max(runtime.NumCPU()-1, 1)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *Pool) work() {
+	for {
+		// Stop as soon as the pool is closed rather than after the next solve
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		seed := model.RandomSeed()
+		result := solver.Solve(model.NewGame(seed, p.opts), VerifyOptions, p.done)
+		if result.Status != solver.Solvable {
+			continue
+		}
+
+		// Wait for room in the pool, giving up if it is closed meanwhile
+		deal := Deal{Seed: seed, Moves: len(result.Moves)}
+		select {
+		case p.deals <- deal:
+			log.Printf("Verified winnable deal: seed %d (%d moves)", deal.Seed, deal.Moves)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Next returns a verified deal if one is ready, without waiting for the
+// search to find one.
+func (p *Pool) Next() (Deal, bool) {
+	select {
+	case deal := <-p.deals:
+		return deal, true
+	default:
+		return Deal{}, false
+	}
+}
+
+// Close stops the background search, cancelling any deal being solved.
+func (p *Pool) Close() {
+	close(p.done)
+}
@@ -1,100 +1,170 @@
 package game
 
 import (
-	"image/color"
 	"log"
+	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"urffer.xyz/go-solitaire/src/animation"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/scoring"
+	"urffer.xyz/go-solitaire/src/solver"
 	"urffer.xyz/go-solitaire/src/util"
 )
 
 const DEFAULT_CARD_SPACING = 10
 const DEFAULT_CARD_INTERPILE_SPACING = 20
+const DEFAULT_CARD_FAN_SPACING = 30
+const AUTO_FINISH_VELOCITY = 0.5
+const DEFAULT_SCREEN_WIDTH = 1000
+const DEFAULT_SCREEN_HEIGHT = 800
 
-var POS_DRAW_PILE = util.Pos[float64]{
-	X: DEFAULT_CARD_SPACING,
-	Y: DEFAULT_CARD_SPACING,
+// LAYOUT_EASING is the fraction of the way to their new places stacks move
+// each tick after the window is resized.
+const LAYOUT_EASING = 0.2
+
+func NewBoard(seed uint64, opts model.Options, scorer scoring.Scorer) *Board {
+	return NewBoardFromState(model.NewGame(seed, opts), scorer)
 }
-var POS_OVERTURNED_PILE = POS_DRAW_PILE.Translate(
-	DEFAULT_CARD_WIDTH+DEFAULT_CARD_SPACING,
-	0,
-)
 
-func NewBoard() *Board {
-	// Create a deck of cards
-	deck := &CardStack{
-		Cards:    []*Card{},
-		basePos:  POS_DRAW_PILE,
-		isSpread: false,
+// NewBoardFromState builds the on-screen board for an existing game state.
+func NewBoardFromState(state *model.State, scorer scoring.Scorer) *Board {
+	// Create a card image for every card the game is played with
+	cards := map[model.CardID]*Card{}
+	for _, card := range state.Options.Variant().Deck(state.Options) {
+		cards[card.ID()] = MakeCard(card.Number, card.Suit)
 	}
-	for _, suit := range []Suit{Heart, Diamond, Club, Spade} {
-		for _, number := range []Number{Ace, Two, Three, Four, Five, Six, Seven, Eight, Nine, Ten, Jack, Queen, King} {
-			deck.AppendCard(MakeCard(number, suit))
-		}
+
+	b := &Board{
+		state:            state,
+		score:            scoring.NewTally(scorer),
+		cards:            cards,
+		renderGeneration: cardGeneration,
 	}
+	b.layoutStacks()
+	b.syncStacks()
+	return b
+}
 
-	// Shuffle the deck
-	deck.Shuffle()
+// slotPos returns the position of a slot of the layout, which is measured
+// in card-sized steps. The layout is centred across the screen.
+func (b *Board) slotPos(slot model.Slot) util.Pos[float64] {
+	spacing := scaled(DEFAULT_CARD_SPACING)
+	left := spacing
+	if b.screenDims.X > 0 {
+		left += (float64(b.screenDims.X) - scaled(layoutWidth(b.state.Options))) / 2
+	}
+	return util.Pos[float64]{
+		X: left + slot.X*(cardWidth()+spacing),
+		Y: spacing + slot.Y*(cardHeight()+spacing),
+	}
+}
 
-	// Distribute the cards into the working stacks
-	workingStacks := [7]*CardStack{}
-	for i := 0; i < 7; i++ {
-		workingStacks[i] = &CardStack{
-			isSpread: true,
-			basePos: POS_DRAW_PILE.Translate(
-				float64(i*(DEFAULT_CARD_SPACING+DEFAULT_CARD_WIDTH)),
-				float64(DEFAULT_CARD_HEIGHT+DEFAULT_CARD_SPACING),
-			),
-		}
-		for j := 0; j <= i; j++ {
-			cardstack := deck.splitDeckAtIndex(len(deck.Cards) - 1)
-			card := cardstack.GetTopCard()
-			card.IsShown = false
-			workingStacks[i].AppendCard(card)
+// layoutStacks creates a stack for every pile of the game, placed where the
+// rules of the game put it.
+func (b *Board) layoutStacks() {
+	variant := b.state.Options.Variant()
+	for _, id := range b.state.PileIDs() {
+		slot := variant.Slot(b.state.Options, id)
+		stack := &CardStack{
+			pileID:   id,
+			isSpread: slot.Spread,
+			basePos:  b.slotPos(slot),
+			homePos:  b.slotPos(slot),
+			// Cleared cards of a pyramid leave nothing behind
+			hidePlaceholder: id.Kind == model.PileTableau && !slot.Spread,
 		}
-		workingStacks[i].GetTopCard().IsShown = true
-		workingStacks[i].TranslateTo(util.Pos[float64]{
-			X: float64(DEFAULT_CARD_SPACING + i*(DEFAULT_CARD_WIDTH+DEFAULT_CARD_SPACING)),
-			Y: float64(DEFAULT_CARD_SPACING + DEFAULT_CARD_HEIGHT + DEFAULT_CARD_SPACING),
-		})
-	}
-
-	// Put the rest of the deck into the draw pile
-	drawPile := deck
-	drawPile.TranslateTo(POS_DRAW_PILE)
-	drawPile.SetSpread(false)
-	drawPile.SetAllShown(false)
-
-	// Create empty suit piles
-	suitPiles := [4]*CardStack{}
-	for i := 0; i < 4; i++ {
-		suitPiles[i] = &CardStack{
-			Cards:    []*Card{},
-			isSpread: false, // Suit piles only show the top card
-			basePos: POS_OVERTURNED_PILE.Translate(
-				float64((2+i)*(DEFAULT_CARD_WIDTH+DEFAULT_CARD_SPACING)),
-				0,
-			),
+		switch id.Kind {
+		case model.PileStock:
+			b.drawPile = stack
+		case model.PileWaste:
+			stack.fanCount = b.state.StockRules().DrawCount
+			b.overturnedPile = stack
+		case model.PileCell:
+			b.cellStacks = append(b.cellStacks, stack)
+		case model.PileFoundation:
+			b.suitPiles = append(b.suitPiles, stack)
+		case model.PileTableau:
+			b.workingStacks = append(b.workingStacks, stack)
 		}
 	}
+	b.setFocus(b.allStacks()[0].pileID)
+}
 
-	// Create the board with suit piles, working stacks, and empty draw and overturned piles
-	return &Board{
-		suitPiles:     suitPiles,
-		workingStacks: workingStacks,
-		drawPile:      drawPile,
-		overturnedPile: &CardStack{
-			Cards:    []*Card{},
-			isSpread: false,
-			basePos:  POS_OVERTURNED_PILE,
-		},
+// layoutWidth returns how wide the layout of a game is with cards of the
+// default size.
+func layoutWidth(opts model.Options) float64 {
+	variant := opts.Variant()
+	columns := 0.0
+	for _, id := range variant.Layout(opts).PileIDs() {
+		columns = max(columns, variant.Slot(opts, id).X+1)
+	}
+	return math.Ceil(columns)*(DEFAULT_CARD_WIDTH+DEFAULT_CARD_SPACING) + DEFAULT_CARD_SPACING
+}
+
+// layoutScale returns the card scale at which the layout of a game fills the
+// screen, either across or down.
+func layoutScale(opts model.Options, dims util.Dims) float64 {
+	return min(float64(dims.X)/layoutWidth(opts), float64(dims.Y)/DEFAULT_SCREEN_HEIGHT)
+}
+
+// MinScreenDims returns the screen size the layout of a game needs to show
+// cards at their default size, widened for games with more columns than fit
+// the default screen.
+func MinScreenDims(opts model.Options) util.Dims {
+	return util.Dims{
+		X: max(int(layoutWidth(opts)), DEFAULT_SCREEN_WIDTH),
+		Y: DEFAULT_SCREEN_HEIGHT,
 	}
 }
 
 type Board struct {
-	suitPiles      [4]*CardStack
-	workingStacks  [7]*CardStack
+	state   *model.State
+	history model.History
+	score   *scoring.Tally
+	cards   map[model.CardID]*Card
+
+	// elapsedTicks counts the updates since the first move of the game,
+	// leaving out any time the window was in the background
+	elapsedTicks int
+
+	hudHidden bool
+
+	// hints are the suggested moves for the current state, worked out on demand
+	hints     []model.Move
+	hintIndex int
+
+	// verifiedWinnable is set for deals the solver checked before they were dealt
+	verifiedWinnable bool
+
+	// solverResults receives the answer of a running winnability check, and
+	// closing solverDone cancels it
+	solverResults <-chan solver.Result
+	solverDone    chan struct{}
+	solverResult  *solver.Result
+
+	// autoFinish plays out trivially solved games without being asked
+	autoFinish    bool
+	autoFinishing bool
+
+	// replaying boards only play back recorded moves, so a win is not
+	// celebrated and the recording can still be stepped back through
+	replaying bool
+
+	won        bool
+	finalScore int
+	cascade    *victoryCascade
+	screenDims util.Dims
+	// renderGeneration is the rendering of the card images that the faces of
+	// the cards were rendered with
+	renderGeneration int
+
+	suitPiles     []*CardStack
+	workingStacks []*CardStack
+	// cellStacks holds the free cells, in games that have them
+	cellStacks []*CardStack
+	// drawPile and overturnedPile are nil in games without a stock
 	drawPile       *CardStack
 	overturnedPile *CardStack
 
@@ -104,41 +174,257 @@ type Board struct {
 
 	cursorPos util.Pos[int]
 
+	// focus is the pile the keyboard is on, and focusIndex the card within
+	// it. The focus ring only shows once the keyboard has been used.
+	focus        model.PileID
+	focusIndex   int
+	focusVisible bool
+
 	runningAnimation *animation.Animation
 }
 
-func (b *Board) Draw(screen *ebiten.Image) {
-	// Fill the background with the board color
-	screen.Fill(color.RGBA{
-		R: 0,
-		G: 75,
-		B: 0,
-		A: 255,
-	})
+// State exposes the underlying game state, e.g. for bots and debugging tools.
+func (b *Board) State() *model.State {
+	return b.state
+}
 
-	// Draw all working stacks
-	for _, stack := range b.workingStacks {
-		stack.Draw(screen)
+func (b *Board) Seed() uint64 {
+	return b.state.Seed
+}
+
+// Moves returns the number of moves made so far, not counting undone ones.
+func (b *Board) Moves() int {
+	return len(b.history.Steps())
+}
+
+func (b *Board) Elapsed() time.Duration {
+	return time.Duration(b.elapsedTicks) * time.Second / time.Duration(ebiten.TPS())
+}
+
+// Scorer is the scoring system the game is scored with.
+func (b *Board) Scorer() scoring.Scorer {
+	return b.score.Scorer()
+}
+
+func (b *Board) Score() int {
+	if b.won {
+		return b.finalScore
+	}
+	return b.score.Points(b.Elapsed())
+}
+
+func (b *Board) Won() bool {
+	return b.won
+}
+
+// ReadyForNewGame reports whether the game is over and the victory
+// celebration has finished.
+func (b *Board) ReadyForNewGame() bool {
+	return b.won && b.cascade.Done()
+}
+
+// Abandon settles the score of a game that is being left unfinished and
+// stops checking whether it can be won.
+func (b *Board) Abandon() {
+	b.clearWinnable()
+	if !b.won {
+		b.score.Scorer().GameOver(b.Score())
+	}
+}
+
+// MarkVerifiedWinnable notes that the deal was shown to be winnable before
+// it was dealt.
+func (b *Board) MarkVerifiedWinnable() {
+	b.verifiedWinnable = true
+}
+
+func (b *Board) SetAutoFinish(autoFinish bool) {
+	b.autoFinish = autoFinish
+}
+
+// CanAutoFinish reports whether the game has reached a point where it can be
+// played out automatically.
+func (b *Board) CanAutoFinish() bool {
+	return !b.won && !b.autoFinishing && b.state.CanAutoFinish()
+}
+
+// AutoFinish starts sending every remaining card to the foundations, one
+// after another.
+func (b *Board) AutoFinish() {
+	if !b.CanAutoFinish() || b.Busy() {
+		return
 	}
+	log.Println("Finishing the game automatically")
+	b.autoFinishing = true
+}
 
-	// Draw the draw pile
-	b.drawPile.Draw(screen)
+// SetScreenDims fits the layout to the screen, scaling the cards so that
+// every pile fits. After the first layout the stacks ease into their new
+// places.
+func (b *Board) SetScreenDims(dims util.Dims) {
+	if dims == b.screenDims || dims.X <= 0 || dims.Y <= 0 {
+		return
+	}
+	firstLayout := b.screenDims == (util.Dims{})
+	b.screenDims = dims
 
-	// Draw the overturned pile
-	b.overturnedPile.Draw(screen)
+	setCardScale(layoutScale(b.state.Options, dims))
+	b.refreshCards()
 
-	// Draw the suit piles
-	for _, stack := range b.suitPiles {
+	variant := b.state.Options.Variant()
+	for _, stack := range b.allStacks() {
+		stack.homePos = b.slotPos(variant.Slot(b.state.Options, stack.pileID))
+		if firstLayout {
+			stack.TranslateTo(stack.homePos)
+		} else {
+			// Cards are spread by the new spacing straight away
+			stack.repositionCards()
+		}
+	}
+}
+
+// refreshCards renders the faces of the cards again once the card images
+// have changed, after a change of card size or theme.
+func (b *Board) refreshCards() {
+	if b.renderGeneration == cardGeneration {
+		return
+	}
+	for _, card := range b.cards {
+		card.rerender()
+	}
+	b.renderGeneration = cardGeneration
+}
+
+// settleStacks moves every stack part of the way to where the layout puts it.
+func (b *Board) settleStacks() {
+	for _, stack := range b.allStacks() {
+		delta := stack.homePos.Sub(stack.basePos)
+		switch {
+		case delta.Eq(util.Pos[float64]{}):
+			continue
+		case stack.basePos.AlmostEq(stack.homePos, 0.5):
+			stack.TranslateTo(stack.homePos)
+		default:
+			stack.TranslateBy(util.Pos[float64]{X: delta.X * LAYOUT_EASING, Y: delta.Y * LAYOUT_EASING})
+		}
+	}
+}
+
+// applyMove performs a move through the history and scores it.
+func (b *Board) applyMove(move model.Move) error {
+	step, err := b.history.Apply(b.state, move)
+	if err != nil {
+		return err
+	}
+	b.score.Apply(step, b.state)
+	b.clearHint()
+	b.clearWinnable()
+	return nil
+}
+
+// checkWon ends the game and starts the celebration once every card is home.
+func (b *Board) checkWon() {
+	if b.won || b.replaying || !b.state.IsWon() {
+		return
+	}
+	log.Println("Game won in", b.Elapsed(), "with", len(b.history.Steps()), "moves")
+	b.finalScore = b.score.Final(b.Elapsed())
+	b.score.Scorer().GameOver(b.finalScore)
+	b.won = true
+	// Games that clear cards onto the overturned pile celebrate with that instead
+	stacks := b.suitPiles
+	if len(stacks) == 0 && b.overturnedPile != nil {
+		stacks = []*CardStack{b.overturnedPile}
+	}
+	b.cascade = newVictoryCascade(stacks, b.screenDims)
+}
+
+func (b *Board) allStacks() []*CardStack {
+	stacks := []*CardStack{}
+	if b.drawPile != nil {
+		stacks = append(stacks, b.drawPile)
+	}
+	if b.overturnedPile != nil {
+		stacks = append(stacks, b.overturnedPile)
+	}
+	stacks = append(stacks, b.cellStacks...)
+	stacks = append(stacks, b.suitPiles...)
+	stacks = append(stacks, b.workingStacks...)
+	return stacks
+}
+
+func (b *Board) stack(id model.PileID) *CardStack {
+	for _, stack := range b.allStacks() {
+		if stack.pileID == id {
+			return stack
+		}
+	}
+	return nil
+}
+
+// syncStacks rebuilds every on-screen stack from the game state.
+func (b *Board) syncStacks() {
+	for _, stack := range b.allStacks() {
+		pile := b.state.Pile(stack.pileID)
+		stack.Cards = make([]*Card, 0, pile.Len())
+		for _, card := range pile.Cards {
+			viewCard := b.cards[card.ID()]
+			viewCard.Card = card
+			stack.Cards = append(stack.Cards, viewCard)
+		}
+		stack.repositionCards()
+	}
+}
+
+func (b *Board) Draw(screen *ebiten.Image) {
+	// Cover the background in the felt of the theme
+	drawFelt(screen)
+
+	// Draw every stack on the board
+	for _, stack := range b.allStacks() {
 		stack.Draw(screen)
 	}
+	b.drawStockIndicator(screen)
+
+	// Highlight the current hint and the focus underneath any moving cards
+	b.drawHint(screen)
+	b.drawFocus(screen)
 
 	// Draw the held card stack if it exists
 	if b.heldCardStack != nil {
 		b.heldCardStack.Draw(screen)
 	}
+
+	// Draw the celebration on top of the board once the game is won
+	if b.won {
+		b.cascade.Draw(screen)
+		if b.cascade.Done() {
+			b.drawVictoryPanel(screen)
+		}
+	}
+
+	// Draw the heads-up display over everything else
+	b.drawHud(screen)
 }
 
 func (b *Board) Update() {
+	b.refreshCards()
+
+	// Once the game is won only the celebration moves
+	if b.won {
+		b.cascade.Update()
+		return
+	}
+	b.settleStacks()
+
+	b.pollWinnable()
+
+	// Keep the clock running once the first move has been made, unless the
+	// player has switched to another window
+	if len(b.history.Steps()) > 0 && ebiten.IsFocused() {
+		b.elapsedTicks++
+	}
+
 	if b.runningAnimation != nil {
 		b.runningAnimation.Update()
 		if b.runningAnimation.CurrPos().AlmostEq(b.runningAnimation.TargetPos, 0.01) {
@@ -147,80 +433,114 @@ func (b *Board) Update() {
 		}
 	} else if b.heldCardStack != nil {
 		b.heldCardStack.TranslateTo(b.cursorPos.TranslatePos(b.heldCardOffset).ToFloatPos())
+	} else if b.autoFinishing {
+		b.autoFinishStep()
+	} else if b.autoFinish && !b.replaying && b.CanAutoFinish() {
+		b.AutoFinish()
 	}
 }
 
+// autoFinishStep animates the next card of an automatic finish to its foundation.
+func (b *Board) autoFinishStep() {
+	move, ok := b.state.NextFoundationMove()
+	if !ok {
+		b.autoFinishing = false
+		return
+	}
+	b.animateCards(b.stack(move.From), b.stack(move.To), 1, func() {
+		if err := b.applyMove(move); err != nil {
+			log.Println("Failed to apply move:", err)
+		}
+	})
+	b.runningAnimation.BaseVelocity = AUTO_FINISH_VELOCITY
+}
+
 func (b *Board) SetCusrorPos(pos util.Pos[int]) {
 	b.cursorPos = pos
 }
 
+// pickableStackAtPos finds the card under a position on any stack that
+// cards can be taken from, other than the draw pile. Stacks drawn later
+// overlap earlier ones, so they are tried first.
+func (b *Board) pickableStackAtPos(pos util.Pos[float64]) (*CardStack, int) {
+	stacks := b.allStacks()
+	for i := len(stacks) - 1; i >= 0; i-- {
+		stack := stacks[i]
+		if stack == b.drawPile {
+			continue
+		}
+		if index := stack.IndexAtPos(pos); index >= 0 {
+			return stack, index
+		}
+	}
+	return nil, -1
+}
+
+func (b *Board) pickUp(stack *CardStack, index int) {
+	b.heldCardStack = stack.splitDeckAtIndex(index)
+	b.heldCardResetStack = stack
+	b.heldCardOffset = b.heldCardStack.basePos.ToIntPos().Sub(b.cursorPos)
+}
+
 func (b *Board) MouseDown() {
-	// If there is an ongoing animation, ignore the mouse down event
-	if b.runningAnimation != nil {
-		log.Println("Ignoring mouse down event, animation is running.")
+	// Clicking during the celebration skips straight to the victory panel
+	if b.won {
+		b.cascade.Skip()
 		return
 	}
 
-	// Try picking cards up from one of the working stacks
-	for _, stack := range b.workingStacks {
-		if newStack := stack.SplitDeckAtPos(b.cursorPos.ToFloatPos()); newStack != nil {
-			if !newStack.Cards[0].IsShown {
-				log.Println("Cannot pick up a stack of cards where the bottom card is not shown.")
-				stack.AppendStack(newStack)
-			} else {
-				log.Println("Sub-stack picked up")
-				b.heldCardStack = newStack
-				b.heldCardResetStack = stack
-				b.heldCardOffset = b.heldCardStack.basePos.ToIntPos().Sub(b.cursorPos)
-			}
-			return
-		}
+	// If there is an ongoing animation, ignore the mouse down event
+	if b.runningAnimation != nil || b.autoFinishing {
+		log.Println("Ignoring mouse down event, animation is running.")
+		return
 	}
-
-	// Try picking cards up from one of the suit piles
-	for _, stack := range b.suitPiles {
-		if newStack := stack.SplitDeckAtPos(b.cursorPos.ToFloatPos()); newStack != nil {
-			log.Println("Card grabbed from suit pile:", newStack)
-			b.heldCardStack = newStack
-			b.heldCardResetStack = stack
-			b.heldCardOffset = b.heldCardStack.basePos.ToIntPos().Sub(b.cursorPos)
+	b.clearHint()
+	b.focusVisible = false
+
+	// Try picking cards up from one of the working stacks, the suit piles or the overturned pile
+	pos := b.cursorPos.ToFloatPos()
+	if stack, index := b.pickableStackAtPos(pos); stack != nil {
+		if !b.state.CanPickUp(stack.pileID, index) {
+			log.Println("Cannot pick up cards from", stack.pileID, "at index", index)
 			return
 		}
+		log.Println("Sub-stack picked up from", stack.pileID)
+		b.pickUp(stack, index)
+		return
 	}
 
-	// Try picking a card up from the draw pile
-	if b.drawPile.BaseCardContains(b.cursorPos.ToFloatPos()) {
-		if topCard := b.drawPile.GetTopCard(); topCard != nil {
+	// Try drawing from the draw pile, or recycling the overturned pile if it is empty
+	if b.drawPile != nil && b.drawPile.BaseCardContains(pos) {
+		// When drawing a single card it can be dragged straight off the draw pile
+		if b.drawFromStock() && b.state.StockRules().DrawCount == 1 &&
+			b.state.CanPickUp(model.WasteID, len(b.state.Waste.Cards)-1) {
 			log.Println("Card grabbed from draw pile")
-			if newStack := b.drawPile.SplitDeckAtPos(b.cursorPos.ToFloatPos()); newStack != nil {
-				b.heldCardStack = newStack
-				b.heldCardStack.Cards[0].IsShown = true
-				b.heldCardResetStack = b.overturnedPile
-				b.heldCardOffset = b.heldCardStack.basePos.ToIntPos().Sub(b.cursorPos)
-				return
-			}
-		} else if topCard == nil {
-			if len(b.overturnedPile.Cards) > 0 {
-				b.overturnedPile.Reverse()
-				replenishStack := b.overturnedPile.splitDeckAtIndex(0)
-				b.drawPile.AppendStack(replenishStack)
-				b.drawPile.SetAllShown(false)
-			}
+			b.pickUp(b.overturnedPile, len(b.overturnedPile.Cards)-1)
 		}
+		return
 	}
 
-	// Try picking a card up from the overturned pile
-	if topCard := b.overturnedPile.GetTopCard(); topCard != nil && topCard.Contains(b.cursorPos.ToFloatPos()) {
-		log.Println("Card grabbed from overturned pile")
-		if newStack := b.overturnedPile.SplitDeckAtPos(b.cursorPos.ToFloatPos()); newStack != nil {
-			b.heldCardStack = newStack
-			b.heldCardResetStack = b.overturnedPile
-			b.heldCardOffset = b.heldCardStack.basePos.ToIntPos().Sub(b.cursorPos)
-			return
+	log.Println("No card grabbed, cursor not over a working stack or no cards available.")
+}
+
+// drawFromStock draws from the stock, or recycles the overturned pile once
+// the stock is empty. It returns true if cards were drawn.
+func (b *Board) drawFromStock() bool {
+	if b.state.Stock.IsEmpty() {
+		if err := b.applyMove(model.RecycleMove()); err != nil {
+			log.Println("Cannot recycle:", err)
+			return false
 		}
+		b.syncStacks()
+		return false
 	}
-
-	log.Println("No card grabbed, cursor not over a working stack or no cards available.")
+	if err := b.applyMove(model.DrawMove()); err != nil {
+		log.Println("Cannot draw:", err)
+		return false
+	}
+	b.syncStacks()
+	b.checkWon()
+	return true
 }
 
 func (b *Board) MouseUp() {
@@ -236,110 +556,207 @@ func (b *Board) MouseUp() {
 		return
 	}
 
-	// Check if the held stack can be placed onto a working stack
-	for _, stack := range b.workingStacks {
-		topCard := stack.GetTopCard()
-
-		log.Println("Checking if held stack can be placed onto working stack:", stack)
-
-		// If the stack is empty, only a stack with a king as bottom card can be placed on it
-		if topCard == nil {
-			if b.heldCardStack.Cards[0].Number == King {
-				// See if the stack contains the cursor position. If so, append stacks
-				if (&Card{pos: stack.basePos}).Contains(b.cursorPos.ToFloatPos()) {
-					log.Println("Card dropped onto working stack:", stack)
-					if newTopCard := b.heldCardResetStack.GetTopCard(); newTopCard != nil {
-						newTopCard.IsShown = true
-					}
-					b.runningAnimation = b.heldCardStack.CreateAnimationToPos(
-						stack.GetNextCardPos(),
-						func() {
-							stack.AppendStack(b.heldCardStack)
-							b.heldCardStack = nil
-							b.heldCardResetStack = nil
-						},
-					)
-					return
-				}
-			}
-		} else {
-			if topCard.Suit.IsOppositeColor(b.heldCardStack.Cards[0].Suit) &&
-				b.heldCardStack.Cards[0].Number.IsOneLessThan(topCard.Number) {
-				if topCard.Contains(b.cursorPos.ToFloatPos()) {
-					log.Println("Card dropped onto working stack:", stack)
-					if newTopCard := b.heldCardResetStack.GetTopCard(); newTopCard != nil {
-						newTopCard.IsShown = true
-					}
-					b.runningAnimation = b.heldCardStack.CreateAnimationToPos(
-						stack.GetNextCardPos(),
-						func() {
-							stack.AppendStack(b.heldCardStack)
-							b.heldCardStack = nil
-							b.heldCardResetStack = nil
-						},
-					)
-					return
-				}
-			}
+	// Check if the held stack can be placed onto a working stack, a suit pile,
+	// a free cell or the overturned pile
+	pos := b.cursorPos.ToFloatPos()
+	dropStacks := append(append([]*CardStack{}, b.workingStacks...), b.suitPiles...)
+	dropStacks = append(dropStacks, b.cellStacks...)
+	if b.overturnedPile != nil {
+		dropStacks = append(dropStacks, b.overturnedPile)
+	}
+	targets := []*CardStack{}
+	for _, stack := range dropStacks {
+		if stack.DropTargetContains(pos) {
+			targets = append(targets, stack)
 		}
 	}
+	b.dropHeldStack(targets)
+}
 
-	// Check if the held stack can be placed onto a suit pile
-	for i, stack := range b.suitPiles {
-		topCard := stack.GetTopCard()
-
-		// If the held stack has more than one card, it cannot be placed onto a suit pile
-		if len(b.heldCardStack.Cards) > 1 {
-			log.Println("Held stack has more than one card, cannot be placed onto suit pile")
+// dropHeldStack places the held stack onto the first of the targets that
+// takes it, or sends it back where it came from if none does.
+func (b *Board) dropHeldStack(targets []*CardStack) {
+	for _, stack := range targets {
+		move := model.CardsMove(b.heldCardResetStack.pileID, stack.pileID, len(b.heldCardStack.Cards))
+		if err := b.state.Validate(move); err != nil {
+			log.Println("Cannot drop held stack:", err)
 			continue
 		}
 
-		log.Println("Checking if held stack can be placed onto suit pile: ", i)
-
-		// If the stack is empty, only an ace can be placed on it
-		cardCanBePlaced := false
-		if topCard == nil {
-			if b.heldCardStack.Cards[0].Number == Ace {
-				if (&Card{pos: stack.basePos}).Contains(b.cursorPos.ToFloatPos()) {
-					cardCanBePlaced = true
-					log.Println("Card dropped onto suit pile:", stack)
+		log.Println("Card dropped onto", stack.pileID)
+		b.runningAnimation = b.heldCardStack.CreateAnimationToPos(
+			stack.GetNextCardPos(),
+			func() {
+				if err := b.applyMove(move); err != nil {
+					log.Println("Failed to apply move:", err)
 				}
-			}
-		} else {
-			if b.heldCardStack.Cards[0].Suit == topCard.Suit &&
-				b.heldCardStack.Cards[0].Number.IsOneMoreThan(topCard.Number) {
-				if topCard.Contains(b.cursorPos.ToFloatPos()) {
-					cardCanBePlaced = true
-				}
-			}
-		}
-
-		// If the card can be placed, do stuff
-		if cardCanBePlaced {
-			log.Println("Card dropped onto suit pile:", stack)
-			if newTopCard := b.heldCardResetStack.GetTopCard(); newTopCard != nil {
-				newTopCard.IsShown = true
-			}
-			b.runningAnimation = b.heldCardStack.CreateAnimationToPos(
-				stack.GetNextCardPos(),
-				func() {
-					stack.AppendStack(b.heldCardStack)
-					b.heldCardStack = nil
-					b.heldCardResetStack = nil
-				},
-			)
-			return
-		}
+				b.heldCardStack = nil
+				b.heldCardResetStack = nil
+				b.syncStacks()
+				b.checkWon()
+			},
+		)
+		return
 	}
 
 	// No stack was dropped onto, so reset the held stack
 	log.Println("No stack found to drop the held card onto, resetting held card stack.")
+	b.returnHeldStack()
+}
+
+// returnHeldStack animates the held stack back onto the stack it was picked
+// up from.
+func (b *Board) returnHeldStack() {
 	b.runningAnimation = b.heldCardStack.CreateAnimationToPos(
 		b.heldCardResetStack.GetNextCardPos(),
 		func() {
-			b.heldCardResetStack.AppendStack(b.heldCardStack)
 			b.heldCardStack = nil
 			b.heldCardResetStack = nil
+			b.syncStacks()
 		},
 	)
 }
+
+// AutoMove sends the card under the cursor, along with any cards on top of
+// it, to the best place it can legally go. It returns false if there was no
+// card under the cursor to move.
+func (b *Board) AutoMove() bool {
+	if b.Busy() {
+		log.Println("Ignoring auto move, board is busy.")
+		return true
+	}
+
+	stack, index := b.pickableStackAtPos(b.cursorPos.ToFloatPos())
+	if stack == nil || stack.pileID.Kind == model.PileFoundation {
+		return false
+	}
+	if !b.state.CanPickUp(stack.pileID, index) {
+		log.Println("Cannot pick up cards from", stack.pileID, "at index", index)
+		return true
+	}
+	move, ok := b.state.BestMove(stack.pileID, len(stack.Cards)-index)
+	if !ok {
+		log.Println("Nowhere to move cards from", stack.pileID, "at index", index)
+		return true
+	}
+
+	log.Println("Auto moving", move)
+	b.animateCards(stack, b.stack(move.To), move.Count, func() {
+		if err := b.applyMove(move); err != nil {
+			log.Println("Failed to apply move:", err)
+		}
+	})
+	return true
+}
+
+// Busy reports whether the board is in the middle of an animation or a drag.
+func (b *Board) Busy() bool {
+	return b.won || b.autoFinishing || b.runningAnimation != nil || b.heldCardStack != nil
+}
+
+// animateCards lifts the top count cards off one stack and animates them
+// onto another. The game state is left untouched until the cards arrive,
+// at which point onFinish runs and the stacks are rebuilt from the state.
+func (b *Board) animateCards(from, to *CardStack, count int, onFinish func()) {
+	b.clearHint()
+	b.clearWinnable()
+	b.heldCardStack = from.splitDeckAtIndex(len(from.Cards) - count)
+	if b.heldCardStack == nil {
+		onFinish()
+		b.syncStacks()
+		b.checkWon()
+		return
+	}
+	b.runningAnimation = b.heldCardStack.CreateAnimationToPos(
+		to.GetNextCardPos(),
+		func() {
+			onFinish()
+			b.heldCardStack = nil
+			b.heldCardResetStack = nil
+			b.syncStacks()
+			b.checkWon()
+		},
+	)
+}
+
+func (b *Board) Undo() {
+	if b.Busy() {
+		log.Println("Ignoring undo, board is busy.")
+		return
+	}
+	step, ok := b.history.PeekUndo()
+	if !ok {
+		log.Println("Nothing to undo.")
+		return
+	}
+
+	// Rows dealt across the working stacks and moves that cleared cards are
+	// taken back at once
+	log.Println("Undoing", step.Move)
+	dealtRow := step.Move.Kind == model.MoveDraw && b.state.StockRules().DrawCount == 0
+	if dealtRow || len(step.Completed) > 0 {
+		b.clearHint()
+		b.clearWinnable()
+		b.history.Undo(b.state)
+		b.score.Undo()
+		b.syncStacks()
+		return
+	}
+
+	// Send the cards of the step back to where they came from
+	var from, to *CardStack
+	var count int
+	switch step.Move.Kind {
+	case model.MoveDraw:
+		from, to, count = b.overturnedPile, b.drawPile, step.Drawn
+	case model.MoveRecycle:
+		from, to, count = b.drawPile, b.overturnedPile, len(b.drawPile.Cards)
+	default:
+		from, to, count = b.stack(step.Move.To), b.stack(step.Move.From), step.Move.Count
+		if step.Flipped {
+			to.GetTopCard().FaceUp = false
+		}
+		for _, id := range step.Revealed {
+			b.stack(id).GetTopCard().FaceUp = false
+		}
+	}
+	b.animateCards(from, to, count, func() {
+		b.history.Undo(b.state)
+		b.score.Undo()
+	})
+}
+
+func (b *Board) Redo() {
+	if b.Busy() {
+		log.Println("Ignoring redo, board is busy.")
+		return
+	}
+	step, ok := b.history.PeekRedo()
+	if !ok {
+		log.Println("Nothing to redo.")
+		return
+	}
+
+	// Replay the cards of the step onto their destination
+	log.Println("Redoing", step.Move)
+	var from, to *CardStack
+	var count int
+	switch {
+	case step.Move.Kind == model.MoveDraw && b.state.StockRules().DrawCount == 0:
+		// A row dealt across the working stacks has nothing to animate, so it appears at once
+		from, to, count = b.drawPile, b.drawPile, 0
+	case step.Move.Kind == model.MoveDraw:
+		from, to, count = b.drawPile, b.overturnedPile, min(b.state.StockRules().DrawCount, len(b.drawPile.Cards))
+	case step.Move.Kind == model.MoveRecycle:
+		from, to, count = b.overturnedPile, b.drawPile, len(b.overturnedPile.Cards)
+	default:
+		from, to, count = b.stack(step.Move.From), b.stack(step.Move.To), step.Move.Count
+	}
+	b.animateCards(from, to, count, func() {
+		if redone, ok := b.history.Redo(b.state); ok {
+			b.score.Apply(redone, b.state)
+		} else {
+			log.Println("Failed to redo", step.Move)
+		}
+	})
+}
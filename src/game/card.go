@@ -3,11 +3,11 @@ package game
 import (
 	"log"
 	"math"
-	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"golang.org/x/text/language"
+	"urffer.xyz/go-solitaire/src/model"
 	"urffer.xyz/go-solitaire/src/util"
 )
 
@@ -16,70 +16,47 @@ const DEFAULT_CARD_HEIGHT = 168
 
 const DEFAULT_NUMBER_SIZE = 30.0
 const DEFAULT_SUIT_SIZE = 60.0
+const DEFAULT_UI_TEXT_SIZE = 16.0
+
+// TOUCH_HIT_SLOP is how far outside a card a finger can land and still touch it.
+const TOUCH_HIT_SLOP = 12.0
 
 var numberTextface *text.GoTextFace = nil
 var suitTextface *text.GoTextFace = nil
+var uiTextface *text.GoTextFace = nil
 var cardBackImage *ebiten.Image = nil
 var cardBlankImage *ebiten.Image = nil
 
-func InitCardsAssets() {
-	// Load font file
-	reader, err := os.Open("assets/unifont-16.0.04.otf")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer reader.Close()
-
-	// Convert the font file to a GoTextFaceSource
-	font, err := text.NewGoTextFaceSource(reader)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Create the textfaces
-	numberTextface = &text.GoTextFace{
-		Source:    font,
-		Direction: text.DirectionLeftToRight,
-		Size:      DEFAULT_NUMBER_SIZE,
-		Language:  language.English,
-	}
-	suitTextface = &text.GoTextFace{
-		Source:    font,
-		Direction: text.DirectionLeftToRight,
-		Size:      DEFAULT_SUIT_SIZE,
-		Language:  language.English,
-	}
+// The images cards are rendered from, as loaded from the assets
+var cardBackSource *ebiten.Image = nil
+var cardBlankSource *ebiten.Image = nil
+var numberSources = map[model.Number]*ebiten.Image{}
 
-	// Load the card back image
-	cardBackImage, err = util.LoadEbitenImageFromFile("assets/card_back.png")
-	if err != nil {
-		log.Fatal(err)
-	}
+// cardScale is the size cards are drawn at relative to the default size. It
+// follows the size of the window.
+var cardScale = 1.0
 
-	// Scale the card back image to fit the default card dimensions
-	cardBackImage = util.ScaleEbitenImage(
-		cardBackImage,
-		util.Dims{X: DEFAULT_CARD_WIDTH, Y: DEFAULT_CARD_HEIGHT},
-	)
+// cardGeneration counts the times the card images were rendered, so that
+// boards know when to render the faces of their cards again.
+var cardGeneration = 0
 
-	// Load the blank card image
-	cardBlankImage, err = util.LoadEbitenImageFromFile("assets/card_blank.png")
-	if err != nil {
-		log.Fatal(err)
-	}
+// hitSlop is the margin around the outside of a stack that counts as being
+// on it.
+var hitSlop = 0.0
 
-	// Scale the blank card image to fit the default card dimensions
-	cardBlankImage = util.ScaleEbitenImage(
-		cardBlankImage,
-		util.Dims{X: DEFAULT_CARD_WIDTH, Y: DEFAULT_CARD_HEIGHT},
-	)
+// deviceScale is how many screen pixels make up a logical pixel, which is
+// more than one on high density displays.
+var deviceScale = 1.0
 
+// InitCardsAssets loads the images every theme shares and switches to the
+// given theme.
+func InitCardsAssets(theme *Theme) {
 	// Load suit images
-	suitImagePaths := map[Suit]string{
-		Heart:   "assets/suit_heart.png",
-		Diamond: "assets/suit_diamond.png",
-		Club:    "assets/suit_club.png",
-		Spade:   "assets/suit_spade.png",
+	suitImagePaths := map[model.Suit]string{
+		model.Heart:   "assets/suit_heart.png",
+		model.Diamond: "assets/suit_diamond.png",
+		model.Club:    "assets/suit_club.png",
+		model.Spade:   "assets/suit_spade.png",
 	}
 	for suit, imagePath := range suitImagePaths {
 		image, err := util.LoadEbitenImageFromFile(imagePath)
@@ -90,131 +67,211 @@ func InitCardsAssets() {
 	}
 
 	// Load number images
-	numberImagePaths := map[Number]string{
-		Ace:   "assets/num_1-ace.png",
-		Two:   "assets/num_2.png",
-		Three: "assets/num_3.png",
-		Four:  "assets/num_4.png",
-		Five:  "assets/num_5.png",
-		Six:   "assets/num_6.png",
-		Seven: "assets/num_7.png",
-		Eight: "assets/num_8.png",
-		Nine:  "assets/num_9.png",
+	numberImagePaths := map[model.Number]string{
+		model.Ace:   "assets/num_1-ace.png",
+		model.Two:   "assets/num_2.png",
+		model.Three: "assets/num_3.png",
+		model.Four:  "assets/num_4.png",
+		model.Five:  "assets/num_5.png",
+		model.Six:   "assets/num_6.png",
+		model.Seven: "assets/num_7.png",
+		model.Eight: "assets/num_8.png",
+		model.Nine:  "assets/num_9.png",
 	}
 	for number, imagePath := range numberImagePaths {
 		image, err := util.LoadEbitenImageFromFile(imagePath)
 		if err != nil {
 			log.Fatalf("Failed to load number image for %s: %v", number, err)
 		}
-		// Scale the number image to fit the default card dimensions
-		image = util.ScaleEbitenImage(
-			image,
-			util.Dims{X: DEFAULT_CARD_WIDTH, Y: DEFAULT_CARD_HEIGHT},
-		)
-		NumberImages[number] = image
+		numberSources[number] = image
+	}
+
+	if err := ApplyTheme(theme); err != nil {
+		log.Fatalf("Failed to load theme %s: %v", theme.Name, err)
+	}
+}
+
+// createTextfaces creates the textfaces from a font, at the size text is
+// drawn at on the screen.
+func createTextfaces(font *text.GoTextFaceSource) {
+	numberTextface = &text.GoTextFace{
+		Source:    font,
+		Direction: text.DirectionLeftToRight,
+		Size:      uiScaled(DEFAULT_NUMBER_SIZE),
+		Language:  language.English,
+	}
+	suitTextface = &text.GoTextFace{
+		Source:    font,
+		Direction: text.DirectionLeftToRight,
+		Size:      uiScaled(DEFAULT_SUIT_SIZE),
+		Language:  language.English,
+	}
+	uiTextface = &text.GoTextFace{
+		Source:    font,
+		Direction: text.DirectionLeftToRight,
+		Size:      uiScaled(DEFAULT_UI_TEXT_SIZE),
+		Language:  language.English,
+	}
+}
+
+// renderCardImages renders the card back, the blank card and the corner
+// numbers in the active theme at the current card size.
+func renderCardImages() {
+	dims := cardDims()
+	for _, image := range []*ebiten.Image{cardBackImage, cardBlankImage, placeholderImage} {
+		if image != nil {
+			image.Deallocate()
+		}
+	}
+	cardBackImage = util.ScaleEbitenImage(cardBackSource, dims)
+	if tint := activeTheme.CardBackTint; tint.IsSet() {
+		tinted := ebiten.NewImage(dims.X, dims.Y)
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.ScaleWithColor(tint.Color())
+		tinted.DrawImage(cardBackImage, op)
+		cardBackImage.Deallocate()
+		cardBackImage = tinted
 	}
+	cardBlankImage = util.ScaleEbitenImage(cardBlankSource, dims)
+	renderPlaceholder()
 
-	// Generate remaining number images
-	for _, number := range []Number{
-		Ten, Jack, Queen, King,
-	} {
-		numberSymbol := NumberSymbols[number]
-		numberOps := &text.DrawOptions{}
-		numberOps.GeoM.Scale(4.0, 4.0)
-		newImage := ebiten.NewImage(DEFAULT_CARD_WIDTH, DEFAULT_CARD_HEIGHT)
+	// Numbers are drawn into a corner a quarter of the size of the card
+	corner := util.Dims{X: dims.X / 4, Y: dims.Y / 4}
+	for _, number := range model.Numbers {
+		if source := numberSources[number]; source != nil && activeTheme.FaceStyle == FACE_STYLE_CLASSIC {
+			NumberImages[number] = util.ScaleEbitenImage(source, corner)
+			continue
+		}
+
+		// Generate the remaining number images, with the text laid out at the
+		// card size rather than scaled so that it stays sharp
+		cornerTextface := &text.GoTextFace{
+			Source:    numberTextface.Source,
+			Direction: text.DirectionLeftToRight,
+			Size:      scaled(DEFAULT_NUMBER_SIZE),
+			Language:  language.English,
+		}
+		newImage := ebiten.NewImage(corner.X, corner.Y)
 		text.Draw(
 			newImage,
-			numberSymbol,
-			numberTextface,
-			numberOps,
+			model.NumberSymbols[number],
+			cornerTextface,
+			&text.DrawOptions{},
 		)
 		NumberImages[number] = newImage
 	}
+	cardGeneration++
+}
+
+// cardDims returns the size cards are drawn at.
+func cardDims() util.Dims {
+	return util.Dims{
+		X: int(math.Round(DEFAULT_CARD_WIDTH * cardScale)),
+		Y: int(math.Round(DEFAULT_CARD_HEIGHT * cardScale)),
+	}
 }
 
+func cardWidth() float64 {
+	return float64(cardDims().X)
+}
+
+func cardHeight() float64 {
+	return float64(cardDims().Y)
+}
+
+// scaled converts a length on a board of default-sized cards to the current
+// card size.
+func scaled(length float64) float64 {
+	return length * cardScale
+}
+
+// setCardScale changes the size cards are drawn at, rendering the shared card
+// images again. Faces of existing cards have to be rendered again with
+// Card.rerender.
+func setCardScale(scale float64) {
+	if scale == cardScale {
+		return
+	}
+	cardScale = scale
+	renderCardImages()
+}
+
+// Card is the on-screen representation of a model.Card. The embedded model
+// card is a copy that the board refreshes from the game state after each move.
 type Card struct {
-	Number  Number
-	Suit    Suit
-	IsShown bool
+	model.Card
 
 	image *ebiten.Image
 	pos   util.Pos[float64]
 }
 
 func MakeCard(
-	number Number,
-	suit Suit,
+	number model.Number,
+	suit model.Suit,
 ) *Card {
+	return &Card{
+		Card: model.Card{
+			Number: number,
+			Suit:   suit,
+			FaceUp: true,
+		},
+		image: renderCardFace(number, suit),
+
+		pos: util.Pos[float64]{X: 50, Y: 100},
+	}
+}
+
+// rerender renders the face of the card again after the card size changed.
+func (c *Card) rerender() {
+	c.image.Deallocate()
+	c.image = renderCardFace(c.Number, c.Suit)
+}
+
+// renderCardFace draws the face of a card at the current card size.
+func renderCardFace(number model.Number, suit model.Suit) *ebiten.Image {
 	image := ebiten.NewImageFromImage(cardBlankImage)
+	dims := cardDims()
+	suitColor := activeTheme.suitColor(suit)
 
 	// Draw the suit in the center of the card
 	suitImage := SuitImages[suit]
 	suitOps := &ebiten.DrawImageOptions{}
-	if suit == Heart || suit == Diamond {
-		// Red suits
-		suitOps.ColorScale.SetR(1.0)
-		suitOps.ColorScale.SetG(0.0)
-		suitOps.ColorScale.SetB(0.0)
-	} else {
-		// Black suits
-		suitOps.ColorScale.SetR(0.0)
-		suitOps.ColorScale.SetG(0.0)
-		suitOps.ColorScale.SetB(0.0)
-	}
+	suitOps.ColorScale.ScaleWithColor(suitColor)
 	suitImage = util.ScaleEbitenImage(
 		suitImage,
-		util.Dims{X: suitImage.Bounds().Dx() / 4, Y: suitImage.Bounds().Dy() / 4},
+		util.Dims{
+			X: int(math.Round(float64(suitImage.Bounds().Dx()) / 4 * cardScale)),
+			Y: int(math.Round(float64(suitImage.Bounds().Dy()) / 4 * cardScale)),
+		},
 	)
 	suitOps.GeoM.Translate(
-		float64(DEFAULT_CARD_WIDTH)/2.0-float64(suitImage.Bounds().Dx())/2.0,
-		float64(DEFAULT_CARD_HEIGHT)/2.0-float64(suitTextface.Size)/2.0,
+		float64(dims.X)/2.0-float64(suitImage.Bounds().Dx())/2.0,
+		float64(dims.Y)/2.0-scaled(DEFAULT_SUIT_SIZE)/2.0,
 	)
 	image.DrawImage(suitImage, suitOps)
 
 	// Draw the number on the card in each corner
 	if numberImage := NumberImages[number]; numberImage != nil {
 		numberOps := &ebiten.DrawImageOptions{}
-		if suit == Heart || suit == Diamond {
-			// Red suits
-			numberOps.ColorScale.SetR(1.0)
-			numberOps.ColorScale.SetG(0.0)
-			numberOps.ColorScale.SetB(0.0)
-		} else {
-			// Black suits
-			numberOps.ColorScale.SetR(0.0)
-			numberOps.ColorScale.SetG(0.0)
-			numberOps.ColorScale.SetB(0.0)
-		}
-		numberImage = util.ScaleEbitenImage(
-			numberImage,
-			util.Dims{X: numberImage.Bounds().Dx() / 4, Y: numberImage.Bounds().Dy() / 4},
-		)
+		numberOps.ColorScale.ScaleWithColor(suitColor)
 		image.DrawImage(numberImage, numberOps)
 		numberOps.GeoM.Rotate(math.Pi)
 		numberOps.GeoM.Translate(
-			float64(DEFAULT_CARD_WIDTH),
-			float64(DEFAULT_CARD_HEIGHT),
+			float64(dims.X),
+			float64(dims.Y),
 		)
 		image.DrawImage(numberImage, numberOps)
 	} else {
 		log.Fatalf("No image found for number %s", number)
 	}
 
-	// Return the card with the complete image
-	return &Card{
-		Number:  number,
-		Suit:    suit,
-		IsShown: true,
-		image:   image,
-
-		pos: util.Pos[float64]{X: 50, Y: 100},
-	}
+	return image
 }
 
 func (c *Card) Draw(screen *ebiten.Image) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(c.pos.X), float64(c.pos.Y))
-	if !c.IsShown {
+	if !c.FaceUp {
 		// If the card is not shown, draw the card back
 		screen.DrawImage(cardBackImage, op)
 	} else {
@@ -222,11 +279,46 @@ func (c *Card) Draw(screen *ebiten.Image) {
 	}
 }
 
-func (c *Card) String() string {
-	return NumberSymbols[c.Number] + SuitSymbols[c.Suit]
+// Contains reports whether a position is on the card.
+func (c *Card) Contains(pos util.Pos[float64]) bool {
+	return pos.X >= c.pos.X && pos.X <= c.pos.X+cardWidth() &&
+		pos.Y >= c.pos.Y && pos.Y <= c.pos.Y+cardHeight()
 }
 
-func (c *Card) Contains(pos util.Pos[float64]) bool {
-	return pos.X >= c.pos.X && pos.X <= c.pos.X+DEFAULT_CARD_WIDTH &&
-		pos.Y >= c.pos.Y && pos.Y <= c.pos.Y+DEFAULT_CARD_HEIGHT
+// NearlyContains reports whether a position is on the card, or near enough
+// to it while playing by touch. Only cards that nothing overlaps are widened
+// like this, so that covered cards keep all of their visible strip.
+func (c *Card) NearlyContains(pos util.Pos[float64]) bool {
+	return pos.X >= c.pos.X-hitSlop && pos.X <= c.pos.X+cardWidth()+hitSlop &&
+		pos.Y >= c.pos.Y-hitSlop && pos.Y <= c.pos.Y+cardHeight()+hitSlop
+}
+
+// SetTouchInput widens the area around cards that counts as touching them,
+// since a finger is less precise than the mouse.
+func SetTouchInput(touch bool) {
+	if touch {
+		hitSlop = uiScaled(TOUCH_HIT_SLOP)
+	} else {
+		hitSlop = 0
+	}
+}
+
+// SetDeviceScale sets how many screen pixels make up a logical pixel. The
+// interface is scaled up to keep its size, and text is laid out at the new
+// size so that it is drawn at the full density of the screen.
+func SetDeviceScale(scale float64) {
+	if scale == deviceScale {
+		return
+	}
+	deviceScale = scale
+	numberTextface.Size = uiScaled(DEFAULT_NUMBER_SIZE)
+	suitTextface.Size = uiScaled(DEFAULT_SUIT_SIZE)
+	uiTextface.Size = uiScaled(DEFAULT_UI_TEXT_SIZE)
+	SetTouchInput(hitSlop > 0)
+}
+
+// uiScaled converts a length of the interface in logical pixels to screen
+// pixels.
+func uiScaled(length float64) float64 {
+	return length * deviceScale
 }
@@ -1,32 +1,53 @@
 package game
 
 import (
-	"image/color"
 	"log"
-	"math/rand"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"urffer.xyz/go-solitaire/src/animation"
+	"urffer.xyz/go-solitaire/src/model"
 	"urffer.xyz/go-solitaire/src/util"
 )
 
 var placeholderImage *ebiten.Image = nil
 
-func InitCardStackBkg() {
-	placeholderImage = ebiten.NewImage(DEFAULT_CARD_WIDTH, DEFAULT_CARD_HEIGHT)
-	placeholderImage.Fill(color.RGBA{
-		R: 0,
-		G: 150,
-		B: 0,
-		A: 255,
-	})
+// renderPlaceholder renders the base of empty stacks at the current card size.
+func renderPlaceholder() {
+	dims := cardDims()
+	placeholderImage = ebiten.NewImage(dims.X, dims.Y)
+	fill := activeTheme.Placeholder.Color()
+	if activeTheme.PlaceholderStyle == PLACEHOLDER_STYLE_OUTLINE {
+		strokeWidth := scaled(PLACEHOLDER_STROKE_WIDTH)
+		vector.StrokeRect(
+			placeholderImage,
+			float32(strokeWidth/2),
+			float32(strokeWidth/2),
+			float32(float64(dims.X)-strokeWidth),
+			float32(float64(dims.Y)-strokeWidth),
+			float32(strokeWidth),
+			fill,
+			true,
+		)
+	} else {
+		placeholderImage.Fill(fill)
+	}
 }
 
 type CardStack struct {
 	Cards []*Card
 
-	basePos  util.Pos[float64]
+	pileID  model.PileID
+	basePos util.Pos[float64]
+	// homePos is where the layout puts the stack. After the window is
+	// resized the stack eases from its base position towards it.
+	homePos  util.Pos[float64]
 	isSpread bool
+	// fanCount is the number of top cards fanned out sideways on a stack
+	// that is not spread, as on the overturned pile in draw-three games.
+	fanCount int
+	// hidePlaceholder leaves nothing behind once the stack is empty
+	hidePlaceholder bool
 }
 
 func (c *CardStack) GetTopCard() *Card {
@@ -42,14 +63,13 @@ func (c *CardStack) Draw(screen *ebiten.Image) {
 			card.Draw(screen)
 		}
 	} else {
-		if len(c.Cards) > 0 {
-			// Draw only the top card of the stack
-			topCard := c.Cards[len(c.Cards)-1]
-			topCard.Draw(screen)
+		// Draw only the top card of the stack, or the fanned cards at the top
+		for _, card := range c.Cards[max(len(c.Cards)-max(c.fanCount, 1), 0):] {
+			card.Draw(screen)
 		}
 	}
 
-	if len(c.Cards) == 0 {
+	if len(c.Cards) == 0 && !c.hidePlaceholder {
 		// Draw a placeholder for the base of the stack
 		op := &ebiten.DrawImageOptions{}
 		op.GeoM.Translate(c.basePos.ToTuple())
@@ -81,13 +101,6 @@ func (c *CardStack) SetSpread(spread bool) {
 	c.repositionCards()
 }
 
-func (c *CardStack) SetAllShown(shown bool) {
-	// Set the visibility of all cards in the stack
-	for _, card := range c.Cards {
-		card.IsShown = shown
-	}
-}
-
 func (c *CardStack) AppendStack(other *CardStack) {
 	if other == nil || len(other.Cards) == 0 {
 		return // Nothing to append
@@ -122,16 +135,6 @@ func (c *CardStack) Reverse() {
 	c.repositionCards()
 }
 
-func (c *CardStack) Shuffle() {
-	for i := len(c.Cards) - 1; i > 0; i-- {
-		j := rand.Intn(i + 1)
-		c.Cards[i], c.Cards[j] = c.Cards[j], c.Cards[i]
-	}
-
-	// Reposition cards after shuffling
-	c.repositionCards()
-}
-
 func (c *CardStack) GetNextCardPos() util.Pos[float64] {
 	// Get the position of the next card in the stack
 	if len(c.Cards) == 0 {
@@ -139,7 +142,9 @@ func (c *CardStack) GetNextCardPos() util.Pos[float64] {
 	}
 
 	if c.isSpread {
-		return c.GetTopCard().pos.Translate(0, DEFAULT_CARD_INTERPILE_SPACING)
+		return c.GetTopCard().pos.Translate(0, scaled(DEFAULT_CARD_INTERPILE_SPACING))
+	} else if c.fanCount > 1 {
+		return c.basePos.Translate(float64(min(len(c.Cards), c.fanCount-1))*scaled(DEFAULT_CARD_FAN_SPACING), 0)
 	} else {
 		return c.basePos
 	}
@@ -167,9 +172,12 @@ func (c *CardStack) CreateAnimationToPos(targetPos util.Pos[float64], onFinishAc
 
 func (c *CardStack) repositionCards() {
 	// Reposition all cards in the stack based on the base position
+	firstFanned := max(len(c.Cards)-c.fanCount, 0)
 	for i, card := range c.Cards {
 		if c.isSpread {
-			card.pos = c.basePos.Translate(0, float64(i*DEFAULT_CARD_INTERPILE_SPACING))
+			card.pos = c.basePos.Translate(0, float64(i)*scaled(DEFAULT_CARD_INTERPILE_SPACING))
+		} else if c.fanCount > 1 && i > firstFanned {
+			card.pos = c.basePos.Translate(float64(i-firstFanned)*scaled(DEFAULT_CARD_FAN_SPACING), 0)
 		} else {
 			card.pos = c.basePos
 		}
@@ -192,26 +200,59 @@ func (c *CardStack) splitDeckAtIndex(index int) *CardStack {
 	return newStack
 }
 
-func (c *CardStack) SplitDeckAtPos(pos util.Pos[float64]) *CardStack {
-	// Find the index of the card that contains the given position
+// IndexAtPos finds the topmost card at a position. While playing by touch a
+// position just outside the stack counts as being on the nearest card, but
+// the cards within the stack are not widened.
+func (c *CardStack) IndexAtPos(pos util.Pos[float64]) int {
+	if index := c.cardIndexAtPos(pos); index >= 0 || len(c.Cards) == 0 {
+		return index
+	}
+
+	// Bring a position within the slop around the stack onto its edge
+	first, last := c.Cards[0].pos, c.GetTopCard().pos.Translate(cardWidth(), cardHeight())
+	if pos.X < first.X-hitSlop || pos.X > last.X+hitSlop || pos.Y < first.Y-hitSlop || pos.Y > last.Y+hitSlop {
+		return -1
+	}
+	pos.X = min(max(pos.X, first.X), last.X)
+	pos.Y = min(max(pos.Y, first.Y), last.Y)
+	return c.cardIndexAtPos(pos)
+}
+
+func (c *CardStack) cardIndexAtPos(pos util.Pos[float64]) int {
+	// Find the index of the topmost card that contains the given position
 	for i, card := range c.Cards {
 		if card.Contains(pos) {
 			if i < len(c.Cards)-1 {
-				// Split deck at the current card if it's not the last one
+				// Use the current card if the next one doesn't overlap the position
 				if !c.Cards[i+1].Contains(pos) {
-					return c.splitDeckAtIndex(i)
+					return i
 				}
 			} else {
-				// Split deck on the last card
-				return c.splitDeckAtIndex(i)
+				// Use the last card
+				return i
 			}
 		}
 	}
+	return -1 // No card found at the given position
+}
+
+func (c *CardStack) SplitDeckAtPos(pos util.Pos[float64]) *CardStack {
+	if index := c.IndexAtPos(pos); index >= 0 {
+		return c.splitDeckAtIndex(index)
+	}
 	return nil // No card found at the given position
 }
 
 func (c *CardStack) BaseCardContains(pos util.Pos[float64]) bool {
 	// Check if the base position of the stack contains the given position
-	return pos.X >= c.basePos.X && pos.X <= c.basePos.X+DEFAULT_CARD_WIDTH &&
-		pos.Y >= c.basePos.Y && pos.Y <= c.basePos.Y+DEFAULT_CARD_HEIGHT
+	return pos.X >= c.basePos.X-hitSlop && pos.X <= c.basePos.X+cardWidth()+hitSlop &&
+		pos.Y >= c.basePos.Y-hitSlop && pos.Y <= c.basePos.Y+cardHeight()+hitSlop
+}
+
+func (c *CardStack) DropTargetContains(pos util.Pos[float64]) bool {
+	// Cards are dropped onto the top card, or onto the base of an empty stack
+	if topCard := c.GetTopCard(); topCard != nil {
+		return topCard.NearlyContains(pos)
+	}
+	return c.BaseCardContains(pos)
 }
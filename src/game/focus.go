@@ -0,0 +1,236 @@
+package game
+
+import (
+	"image/color"
+	"log"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+const FOCUS_STROKE_WIDTH = 3
+
+var FOCUS_COLOR = color.RGBA{R: 80, G: 180, B: 255, A: 255}
+
+// Direction is a way the focus can be moved around the board.
+type Direction int
+
+const (
+	DirUp Direction = iota
+	DirDown
+	DirLeft
+	DirRight
+)
+
+func (b *Board) focusedStack() *CardStack {
+	return b.stack(b.focus)
+}
+
+// focusedIndex returns the card of the focused stack that has the focus,
+// which is the top card unless the focus was moved down the stack, or -1 if
+// the stack is empty.
+func (b *Board) focusedIndex() int {
+	return min(max(b.focusIndex, 0), len(b.focusedStack().Cards)-1)
+}
+
+// setFocus moves the focus onto the top card of a pile.
+func (b *Board) setFocus(id model.PileID) {
+	b.focus = id
+	b.focusIndex = math.MaxInt
+}
+
+// focusPos returns the top left corner of the focused card, or of the base
+// of the focused stack if it is empty. While cards are held it is where they
+// would be dropped.
+func (b *Board) focusPos() util.Pos[float64] {
+	stack, index := b.focusedStack(), b.focusedIndex()
+	switch {
+	case b.heldCardStack != nil:
+		return stack.GetNextCardPos()
+	case index >= 0:
+		return stack.Cards[index].pos
+	default:
+		return stack.basePos
+	}
+}
+
+// updateFocusCursor puts the cursor on the visible part of the focused card,
+// so that held cards follow the focus around.
+func (b *Board) updateFocusCursor() {
+	b.cursorPos = b.focusPos().Translate(cardWidth()/2, scaled(DEFAULT_CARD_INTERPILE_SPACING)/2).ToIntPos()
+}
+
+// MoveFocus moves the focus to the next card down or up a spread stack, or
+// otherwise to the nearest stack in the given direction.
+func (b *Board) MoveFocus(dir Direction) {
+	if b.won || b.runningAnimation != nil || b.autoFinishing {
+		return
+	}
+	b.focusVisible = true
+	defer b.updateFocusCursor()
+
+	// Only face-up cards can be picked up, so the focus skips the others
+	stack, index := b.focusedStack(), b.focusedIndex()
+	if stack.isSpread && b.heldCardStack == nil && index >= 0 {
+		switch {
+		case dir == DirUp && index > 0 && stack.Cards[index-1].FaceUp:
+			b.focusIndex = index - 1
+			return
+		case dir == DirDown && index < len(stack.Cards)-1:
+			b.focusIndex = index + 1
+			return
+		}
+	}
+	if next := b.stackInDirection(dir); next != nil {
+		b.setFocus(next.pileID)
+	}
+}
+
+// stackInDirection finds the nearest stack in a direction from the focus,
+// favouring stacks that are straight ahead over ones off to the side.
+func (b *Board) stackInDirection(dir Direction) *CardStack {
+	from := b.focusPos()
+	var best *CardStack
+	bestScore := math.Inf(1)
+	for _, stack := range b.allStacks() {
+		if stack.pileID == b.focus || (stack.hidePlaceholder && len(stack.Cards) == 0) {
+			continue
+		}
+
+		// Spread stacks can be reached anywhere along their length
+		target := stack.basePos
+		if top := stack.GetTopCard(); top != nil && stack.isSpread {
+			target.Y = min(max(from.Y, stack.basePos.Y), top.pos.Y)
+		}
+		delta := target.Sub(from)
+		along, across := delta.Y, delta.X
+		switch dir {
+		case DirUp:
+			along = -delta.Y
+		case DirLeft:
+			along, across = -delta.X, delta.Y
+		case DirRight:
+			along, across = delta.X, delta.Y
+		}
+		if along <= 0 {
+			continue
+		}
+		if score := along + 2*math.Abs(across); score < bestScore {
+			best, bestScore = stack, score
+		}
+	}
+	return best
+}
+
+// Activate acts on the focus: it drops held cards onto the focused stack,
+// draws from the draw pile, or picks up the focused card and any cards on
+// top of it.
+func (b *Board) Activate() {
+	// Activating during the celebration skips straight to the victory panel
+	if b.won {
+		b.cascade.Skip()
+		return
+	}
+	if b.runningAnimation != nil || b.autoFinishing {
+		log.Println("Ignoring activation, animation is running.")
+		return
+	}
+	b.focusVisible = true
+	b.clearHint()
+
+	stack, index := b.focusedStack(), b.focusedIndex()
+	switch {
+	case b.heldCardStack != nil:
+		b.dropHeldStack([]*CardStack{stack})
+	case stack == b.drawPile:
+		b.drawFromStock()
+	case index < 0 || !b.state.CanPickUp(stack.pileID, index):
+		log.Println("Cannot pick up cards from", stack.pileID, "at index", index)
+	default:
+		log.Println("Sub-stack picked up from", stack.pileID)
+		b.updateFocusCursor()
+		b.pickUp(stack, index)
+		b.updateFocusCursor()
+	}
+}
+
+// DrawFromStock draws from the stock, or recycles the overturned pile once
+// the stock is empty.
+func (b *Board) DrawFromStock() {
+	if b.Busy() || b.drawPile == nil {
+		log.Println("Ignoring draw, board is busy.")
+		return
+	}
+	b.clearHint()
+	b.drawFromStock()
+}
+
+// CancelHeld sends any held cards back to the stack they were picked up from.
+func (b *Board) CancelHeld() {
+	if b.runningAnimation != nil || b.heldCardStack == nil {
+		return
+	}
+	b.returnHeldStack()
+}
+
+// CycleFoundationMove sends a card to a foundation, taking the piles in turn
+// from the one after the focus so that repeated presses work through every
+// card that can go home. The focus follows the card's old pile.
+func (b *Board) CycleFoundationMove() {
+	if b.Busy() {
+		log.Println("Ignoring foundation move, board is busy.")
+		return
+	}
+	b.focusVisible = true
+
+	ids := b.state.PileIDs()
+	start := slices.Index(ids, b.focus)
+	for i := 1; i <= len(ids); i++ {
+		from := ids[(start+i)%len(ids)]
+		if from.Kind == model.PileFoundation {
+			continue
+		}
+		for j := range b.state.Foundations {
+			move := model.CardsMove(from, model.FoundationID(j), 1)
+			if b.state.Validate(move) != nil {
+				continue
+			}
+			log.Println("Moving to a foundation:", move)
+			b.setFocus(from)
+			b.animateCards(b.stack(from), b.stack(move.To), 1, func() {
+				if err := b.applyMove(move); err != nil {
+					log.Println("Failed to apply move:", err)
+				}
+			})
+			return
+		}
+	}
+	log.Println("No cards can go to a foundation")
+}
+
+// drawFocus rings the focused card, along with any cards on top of it that
+// would be picked up with it.
+func (b *Board) drawFocus(screen *ebiten.Image) {
+	if !b.focusVisible || b.won {
+		return
+	}
+	pos := b.focusPos()
+	height := cardHeight()
+	if stack := b.focusedStack(); b.heldCardStack == nil && len(stack.Cards) > 0 {
+		height += stack.GetTopCard().pos.Y - pos.Y
+	}
+	vector.StrokeRect(
+		screen,
+		float32(pos.X),
+		float32(pos.Y),
+		float32(cardWidth()),
+		float32(height),
+		float32(scaled(FOCUS_STROKE_WIDTH)),
+		FOCUS_COLOR,
+		false,
+	)
+}
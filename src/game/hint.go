@@ -0,0 +1,83 @@
+package game
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+const HINT_STROKE_WIDTH = 4
+
+var HINT_COLOR = color.RGBA{R: 255, G: 215, B: 0, A: 255}
+
+// NextHint shows the next suggested move, cycling back to the best one after
+// the last. The suggestions are worked out again after every move.
+func (b *Board) NextHint() {
+	if b.Busy() {
+		return
+	}
+	if b.hints == nil {
+		b.hints = b.state.Hints()
+		b.hintIndex = 0
+	} else if len(b.hints) > 0 {
+		b.hintIndex = (b.hintIndex + 1) % len(b.hints)
+	}
+	if len(b.hints) > 0 {
+		log.Println("Hint:", b.hints[b.hintIndex])
+	} else {
+		log.Println("No hints available")
+	}
+}
+
+func (b *Board) clearHint() {
+	b.hints = nil
+	b.hintIndex = 0
+}
+
+// hintRects returns the top left corners of the source card and the
+// destination of the current hint.
+func (b *Board) hintRects() (util.Pos[float64], util.Pos[float64], bool) {
+	if len(b.hints) == 0 {
+		return util.Pos[float64]{}, util.Pos[float64]{}, false
+	}
+	move := b.hints[b.hintIndex]
+	switch move.Kind {
+	case model.MoveDraw, model.MoveRecycle:
+		// Rows dealt onto the working stacks only highlight the draw pile
+		if b.overturnedPile == nil || b.state.StockRules().DrawCount == 0 {
+			return b.drawPile.basePos, b.drawPile.basePos, true
+		}
+		return b.drawPile.basePos, b.overturnedPile.GetNextCardPos(), true
+	}
+
+	from, to := b.stack(move.From), b.stack(move.To)
+	source := from.Cards[len(from.Cards)-move.Count].pos
+	destination := to.basePos
+	if topCard := to.GetTopCard(); topCard != nil {
+		destination = topCard.pos
+	}
+	return source, destination, true
+}
+
+func (b *Board) drawHint(screen *ebiten.Image) {
+	source, destination, ok := b.hintRects()
+	if !ok {
+		return
+	}
+	for _, pos := range []util.Pos[float64]{source, destination} {
+		vector.StrokeRect(
+			screen,
+			float32(pos.X),
+			float32(pos.Y),
+			float32(cardWidth()),
+			float32(cardHeight()),
+			float32(scaled(HINT_STROKE_WIDTH)),
+			HINT_COLOR,
+			false,
+		)
+	}
+}
@@ -0,0 +1,88 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const DEFAULT_HUD_ITEM_SPACING = 30
+
+// HUD_HEIGHT is the height of the strip the heads-up display is drawn in.
+const HUD_HEIGHT = DEFAULT_UI_TEXT_SIZE + 2*DEFAULT_CARD_SPACING
+
+func (b *Board) hudItems() []string {
+	minutes, seconds := splitMinutes(b.Elapsed())
+	clock := fmt.Sprintf("Time: %d:%02d", minutes, seconds)
+	if !ebiten.IsFocused() && !b.won {
+		clock += " (paused)"
+	}
+	seed := fmt.Sprintf("Seed: %d", b.Seed())
+	if b.verifiedWinnable {
+		seed += " (winnable)"
+	}
+	items := []string{
+		clock,
+		fmt.Sprintf("Moves: %d", b.Moves()),
+		fmt.Sprintf("Score: %d (%s)", b.Score(), b.score.Scorer().Name()),
+		seed,
+	}
+	if passes := b.state.StockRules().Passes; passes > 0 {
+		items = append(items, fmt.Sprintf("Pass %d of %d", b.state.Recycles+1, passes))
+	}
+	if item, ok := b.winnableHudItem(); ok {
+		items = append(items, item)
+	}
+	if b.hints != nil && len(b.hints) == 0 {
+		items = append(items, "No moves available")
+	}
+	if b.CanAutoFinish() && !b.autoFinish {
+		items = append(items, "Press F to finish automatically")
+	}
+	return items
+}
+
+func (b *Board) SetShowHud(show bool) {
+	b.hudHidden = !show
+}
+
+func (b *Board) drawHud(screen *ebiten.Image) {
+	if b.hudHidden {
+		return
+	}
+
+	// Darken a strip along the bottom of the screen so the text stays readable over cards
+	height := uiScaled(HUD_HEIGHT)
+	top := float64(screen.Bounds().Dy()) - height
+	vector.DrawFilledRect(
+		screen,
+		0,
+		float32(top),
+		float32(screen.Bounds().Dx()),
+		float32(height),
+		color.RGBA{R: 0, G: 0, B: 0, A: 120},
+		false,
+	)
+
+	// Lay the items out left to right in the strip
+	x := uiScaled(DEFAULT_CARD_SPACING)
+	y := top + uiScaled(DEFAULT_CARD_SPACING)
+	for _, item := range b.hudItems() {
+		ops := &text.DrawOptions{}
+		ops.GeoM.Translate(x, y)
+		ops.ColorScale.ScaleWithColor(color.White)
+		text.Draw(screen, item, uiTextface, ops)
+
+		width, _ := text.Measure(item, uiTextface, 0)
+		x += width + uiScaled(DEFAULT_HUD_ITEM_SPACING)
+	}
+}
+
+func splitMinutes(d time.Duration) (int, int) {
+	seconds := int(d / time.Second)
+	return seconds / 60, seconds % 60
+}
@@ -0,0 +1,68 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+// MenuChoice is a game that can be started from the menu.
+type MenuChoice struct {
+	Label   string
+	Options model.Options
+}
+
+var MENU_CHOICES = []MenuChoice{
+	{"Klondike, draw 1", model.Options{Game: model.GameKlondike, DrawCount: 1, Suits: 1}},
+	{"Klondike, draw 3", model.Options{Game: model.GameKlondike, DrawCount: 3, Suits: 1}},
+	{"FreeCell", model.Options{Game: model.GameFreeCell, DrawCount: 1, Suits: 1}},
+	{"Spider, 1 suit", model.Options{Game: model.GameSpider, DrawCount: 1, Suits: 1}},
+	{"Spider, 2 suits", model.Options{Game: model.GameSpider, DrawCount: 1, Suits: 2}},
+	{"Spider, 4 suits", model.Options{Game: model.GameSpider, DrawCount: 1, Suits: 4}},
+	{"Yukon", model.Options{Game: model.GameYukon, DrawCount: 1, Suits: 1}},
+	{"Golf", model.Options{Game: model.GameGolf, DrawCount: 1, Suits: 1}},
+	{"Pyramid", model.Options{Game: model.GamePyramid, DrawCount: 1, Suits: 1}},
+	{"TriPeaks", model.Options{Game: model.GameTriPeaks, DrawCount: 1, Suits: 1}},
+}
+
+// Menu lets the player pick which game to play next.
+type Menu struct {
+	selected int
+}
+
+// NewMenu opens the menu with the game currently being played selected.
+func NewMenu(current model.Options) *Menu {
+	m := &Menu{}
+	for i, choice := range MENU_CHOICES {
+		// The pass limit is kept whichever game is chosen
+		if choice.Options == (model.Options{Game: current.Game, DrawCount: current.DrawCount, Suits: current.Suits}) {
+			m.selected = i
+		}
+	}
+	return m
+}
+
+func (m *Menu) Next() {
+	m.selected = (m.selected + 1) % len(MENU_CHOICES)
+}
+
+func (m *Menu) Prev() {
+	m.selected = (m.selected + len(MENU_CHOICES) - 1) % len(MENU_CHOICES)
+}
+
+func (m *Menu) Selected() MenuChoice {
+	return MENU_CHOICES[m.selected]
+}
+
+// Draw shows the choices on a panel over the board, marking the selected one.
+func (m *Menu) Draw(screen *ebiten.Image) {
+	lines := []string{"New game", ""}
+	for i, choice := range MENU_CHOICES {
+		if i == m.selected {
+			lines = append(lines, "> "+choice.Label+" <")
+		} else {
+			lines = append(lines, choice.Label)
+		}
+	}
+	lines = append(lines, "", "Up and Down to choose, Enter to start, M or Escape to close")
+	DrawPanel(screen, lines)
+}
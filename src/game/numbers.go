@@ -1,46 +1,8 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
-
-type Number int
-
-const (
-	Ace   Number = 1
-	Two   Number = 2
-	Three Number = 3
-	Four  Number = 4
-	Five  Number = 5
-	Six   Number = 6
-	Seven Number = 7
-	Eight Number = 8
-	Nine  Number = 9
-	Ten   Number = 10
-	Jack  Number = 11
-	Queen Number = 12
-	King  Number = 13
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"urffer.xyz/go-solitaire/src/model"
 )
 
-var NumberSymbols = map[Number]string{
-	Ace:   "A",
-	Two:   "2",
-	Three: "3",
-	Four:  "4",
-	Five:  "5",
-	Six:   "6",
-	Seven: "7",
-	Eight: "8",
-	Nine:  "9",
-	Ten:   "10",
-	Jack:  "J",
-	Queen: "Q",
-	King:  "K",
-}
-
-var NumberImages = map[Number]*ebiten.Image{}
-
-func (n Number) IsOneLessThan(other Number) bool {
-	return n == other-1
-}
-func (n Number) IsOneMoreThan(other Number) bool {
-	return n == other+1
-}
+var NumberImages = map[model.Number]*ebiten.Image{}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+const PANEL_PADDING = 20
+
+// DrawPanel draws lines of text centered on a dark panel in the middle of
+// the screen. The first line is the title and is drawn larger.
+func DrawPanel(screen *ebiten.Image, lines []string) {
+	faceFor := func(i int) *text.GoTextFace {
+		if i == 0 {
+			return numberTextface
+		}
+		return uiTextface
+	}
+
+	padding, spacing := uiScaled(PANEL_PADDING), uiScaled(DEFAULT_CARD_SPACING)
+
+	// Size the panel to fit the text
+	panelDims := util.Pos[float64]{X: 0, Y: padding}
+	for i, line := range lines {
+		width, height := text.Measure(line, faceFor(i), 0)
+		panelDims.X = max(panelDims.X, width+2*padding)
+		panelDims.Y += height + spacing
+	}
+	panelDims.Y += padding - spacing
+
+	// Darken the panel in the middle of the screen
+	panelPos := util.Pos[float64]{
+		X: (float64(screen.Bounds().Dx()) - panelDims.X) / 2,
+		Y: (float64(screen.Bounds().Dy()) - panelDims.Y) / 2,
+	}
+	vector.DrawFilledRect(
+		screen,
+		float32(panelPos.X),
+		float32(panelPos.Y),
+		float32(panelDims.X),
+		float32(panelDims.Y),
+		color.RGBA{R: 0, G: 0, B: 0, A: 200},
+		false,
+	)
+
+	// Write the lines centered on the panel
+	y := panelPos.Y + padding
+	for i, line := range lines {
+		width, height := text.Measure(line, faceFor(i), 0)
+		ops := &text.DrawOptions{}
+		ops.GeoM.Translate(panelPos.X+(panelDims.X-width)/2, y)
+		ops.ColorScale.ScaleWithColor(color.White)
+		text.Draw(screen, line, faceFor(i), ops)
+		y += height + spacing
+	}
+}
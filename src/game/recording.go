@@ -0,0 +1,40 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+const RECORDINGS_DIR_NAME = "recordings"
+
+// Recording returns the game so far as its seed and the moves made, leaving
+// out any moves that were undone.
+func (b *Board) Recording() model.Recording {
+	moves := make([]model.Move, 0, len(b.history.Steps()))
+	for _, step := range b.history.Steps() {
+		moves = append(moves, step.Move)
+	}
+	return model.NewRecording(b.state.Seed, b.state.Options, moves)
+}
+
+func SaveRecording(path string, recording model.Recording) error {
+	data, err := json.MarshalIndent(recording, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func LoadRecording(path string) (model.Recording, error) {
+	recording := model.Recording{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return recording, err
+	}
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return recording, err
+	}
+	return recording, recording.Check()
+}
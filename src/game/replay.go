@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/scoring"
+)
+
+// REPLAY_STEP_TICKS is the pause between moves at normal speed.
+const REPLAY_STEP_TICKS = 40
+const REPLAY_BASE_VELOCITY = 0.3
+const REPLAY_MAX_VELOCITY = 0.9
+const REPLAY_MIN_SPEED = 0.25
+const REPLAY_MAX_SPEED = 8.0
+
+// Replay plays a recorded game back on a board. The recorded moves are
+// loaded as the board's redo history, so stepping forwards and backwards
+// uses the same animations as redo and undo.
+type Replay struct {
+	board *Board
+	total int
+
+	paused         bool
+	speed          float64
+	ticksUntilStep int
+}
+
+func NewReplay(recording model.Recording) *Replay {
+	board := NewBoard(recording.Seed, recording.Options, &scoring.Standard{})
+
+	// Redo takes steps from the end of the undone list, so load the moves in reverse
+	undone := make([]model.Step, len(recording.Moves))
+	for i, move := range recording.Moves {
+		undone[len(undone)-1-i] = model.Step{Move: move}
+	}
+	board.history = model.NewHistory(nil, undone)
+	board.replaying = true
+
+	return &Replay{
+		board: board,
+		total: len(recording.Moves),
+		speed: 1,
+	}
+}
+
+func (r *Replay) Board() *Board {
+	return r.board
+}
+
+func (r *Replay) stepForward() {
+	r.board.Redo()
+	if r.board.runningAnimation != nil {
+		r.board.runningAnimation.BaseVelocity = min(REPLAY_BASE_VELOCITY*r.speed, REPLAY_MAX_VELOCITY)
+	}
+	r.ticksUntilStep = int(REPLAY_STEP_TICKS / r.speed)
+}
+
+func (r *Replay) stepBackward() {
+	r.board.Undo()
+	if r.board.runningAnimation != nil {
+		r.board.runningAnimation.BaseVelocity = min(REPLAY_BASE_VELOCITY*r.speed, REPLAY_MAX_VELOCITY)
+	}
+}
+
+func (r *Replay) Update() {
+	r.board.Update()
+
+	// Handle the playback controls
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		r.paused = !r.paused
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		r.paused = true
+		r.stepForward()
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		r.paused = true
+		r.stepBackward()
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		r.speed = min(r.speed*2, REPLAY_MAX_SPEED)
+		log.Println("Replay speed", r.speed)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		r.speed = max(r.speed/2, REPLAY_MIN_SPEED)
+		log.Println("Replay speed", r.speed)
+	}
+
+	// Play the next move once the last one has settled
+	if r.paused || r.board.Busy() {
+		return
+	}
+	if r.ticksUntilStep > 0 {
+		r.ticksUntilStep--
+		return
+	}
+	if r.board.Moves() < r.total {
+		r.stepForward()
+	}
+}
+
+func (r *Replay) status() string {
+	state := "playing"
+	if r.paused {
+		state = "paused"
+	}
+	return fmt.Sprintf(
+		"Replay: move %d/%d, %gx, %s (space: pause, left/right: step, up/down: speed)",
+		r.board.Moves(), r.total, r.speed, state,
+	)
+}
+
+func (r *Replay) Draw(screen *ebiten.Image) {
+	r.board.Draw(screen)
+
+	// Show the playback state along the bottom right, above the board's own display
+	status := r.status()
+	width, _ := text.Measure(status, uiTextface, 0)
+	ops := &text.DrawOptions{}
+	ops.GeoM.Translate(
+		float64(screen.Bounds().Dx())-uiScaled(DEFAULT_CARD_SPACING)-width,
+		float64(screen.Bounds().Dy())-2*uiScaled(DEFAULT_CARD_SPACING+DEFAULT_UI_TEXT_SIZE),
+	)
+	ops.ColorScale.ScaleWithColor(color.White)
+	text.Draw(screen, status, uiTextface, ops)
+}
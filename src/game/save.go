@@ -0,0 +1,96 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/scoring"
+)
+
+// SAVE_VERSION is bumped whenever the layout of SaveFile changes.
+const SAVE_VERSION = 1
+const SAVE_FILE_NAME = "autosave.json"
+
+// SaveFile is the on-disk form of a game in progress.
+type SaveFile struct {
+	Version          int                 `json:"version"`
+	State            *model.State        `json:"state"`
+	Done             []model.Step        `json:"done"`
+	Undone           []model.Step        `json:"undone"`
+	Scoring          string              `json:"scoring"`
+	Score            scoring.TallyRecord `json:"score"`
+	Elapsed          time.Duration       `json:"elapsed"`
+	VerifiedWinnable bool                `json:"verifiedWinnable"`
+}
+
+func (b *Board) Save(path string) error {
+	save := SaveFile{
+		Version:          SAVE_VERSION,
+		State:            b.state,
+		Done:             b.history.Steps(),
+		Undone:           b.history.Undone(),
+		Scoring:          b.score.Scorer().ID(),
+		Score:            b.score.Record(),
+		Elapsed:          b.Elapsed(),
+		VerifiedWinnable: b.verifiedWinnable,
+	}
+	data, err := json.MarshalIndent(save, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a failed save can't clobber the last good one
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// LoadBoard resumes a saved game. It carries on scoring with the given
+// scorer if the game was saved with the same scoring system, so that the
+// scorer's state, like a cumulative bankroll, carries over.
+func LoadBoard(path string, current scoring.Scorer) (*Board, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	save := SaveFile{}
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+	if save.Version != SAVE_VERSION {
+		return nil, fmt.Errorf("unsupported save version %d", save.Version)
+	}
+	if save.State == nil {
+		return nil, fmt.Errorf("save has no game state")
+	}
+	if err := save.State.Options.Validate(); err != nil {
+		return nil, fmt.Errorf("corrupt save: %w", err)
+	}
+	if err := save.State.CheckCards(); err != nil {
+		return nil, fmt.Errorf("corrupt save: %w", err)
+	}
+	history, err := model.ReplayHistory(save.State, save.Done, save.Undone)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt save: %w", err)
+	}
+	scorer := current
+	if scorer == nil || scorer.ID() != save.Scoring {
+		scorer = scoring.New(save.Scoring)
+	}
+	if scorer == nil {
+		return nil, fmt.Errorf("unknown scoring system %q", save.Scoring)
+	}
+
+	b := NewBoardFromState(save.State, scorer)
+	b.history = history
+	b.score = scoring.RestoreTally(scorer, save.Score)
+	b.elapsedTicks = int(save.Elapsed * time.Duration(ebiten.TPS()) / time.Second)
+	b.verifiedWinnable = save.VerifiedWinnable
+	return b, nil
+}
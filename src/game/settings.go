@@ -0,0 +1,99 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SETTINGS_VERSION is bumped whenever the layout of Settings changes.
+const SETTINGS_VERSION = 1
+const SETTINGS_FILE_NAME = "settings.json"
+
+// Settings are the choices the player made in the settings menu, kept
+// between runs.
+type Settings struct {
+	Version int    `json:"version"`
+	Theme   string `json:"theme"`
+
+	path string
+}
+
+// LoadSettings reads the settings file at path, starting with the defaults
+// if there isn't one yet.
+func LoadSettings(path string) (*Settings, error) {
+	s := &Settings{
+		Version: SETTINGS_VERSION,
+		Theme:   DEFAULT_THEME,
+		path:    path,
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Version != SETTINGS_VERSION {
+		return nil, fmt.Errorf("unsupported settings version %d", s.Version)
+	}
+	return s, nil
+}
+
+func (s *Settings) Save() error {
+	data, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// SettingsMenu lets the player switch themes while playing.
+type SettingsMenu struct {
+	themes   []*Theme
+	selected int
+}
+
+// NewSettingsMenu opens the settings with the theme in use selected.
+func NewSettingsMenu(themes []*Theme) *SettingsMenu {
+	return &SettingsMenu{
+		themes:   themes,
+		selected: max(FindTheme(themes, ThemeName()), 0),
+	}
+}
+
+func (m *SettingsMenu) Next() {
+	m.selected = (m.selected + 1) % len(m.themes)
+}
+
+func (m *SettingsMenu) Prev() {
+	m.selected = (m.selected + len(m.themes) - 1) % len(m.themes)
+}
+
+func (m *SettingsMenu) Selected() *Theme {
+	return m.themes[m.selected]
+}
+
+// Draw shows the themes on a panel over the board, marking the selected one
+// and the one in use.
+func (m *SettingsMenu) Draw(screen *ebiten.Image) {
+	lines := []string{"Settings", "", "Theme"}
+	for i, theme := range m.themes {
+		label := theme.Name
+		if theme.Name == ThemeName() {
+			label += " (in use)"
+		}
+		if i == m.selected {
+			label = "> " + label + " <"
+		}
+		lines = append(lines, label)
+	}
+	lines = append(lines, "", "Up and Down to choose, Enter to apply, O or Escape to close")
+	DrawPanel(screen, lines)
+}
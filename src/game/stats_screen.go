@@ -0,0 +1,35 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"urffer.xyz/go-solitaire/src/stats"
+)
+
+func statsLines(store *stats.Store) []string {
+	lines := []string{"Statistics"}
+	if len(store.Records) == 0 {
+		lines = append(lines, "No games played yet")
+	}
+	for _, category := range store.Categories() {
+		r := store.Records[category]
+		lines = append(lines, "", category, fmt.Sprintf(
+			"%d played, %d won (%.0f%%), streak %d, best streak %d",
+			r.Played, r.Won, r.WinRate()*100, r.CurrentStreak, r.BestStreak,
+		))
+		if r.Won > 0 {
+			minutes, seconds := splitMinutes(r.FastestWin)
+			lines = append(lines, fmt.Sprintf(
+				"Fastest win %d:%02d, fewest moves %d, best score %d",
+				minutes, seconds, r.FewestMoves, r.BestScore,
+			))
+		}
+	}
+	return append(lines, "", "Press Delete to reset, S or Escape to close")
+}
+
+// DrawStats shows the player's statistics on a panel over the board.
+func DrawStats(screen *ebiten.Image, store *stats.Store) {
+	DrawPanel(screen, statsLines(store))
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const STOCK_INDICATOR_RADIUS = 30
+const STOCK_INDICATOR_STROKE_WIDTH = 6
+
+var STOCK_RECYCLE_COLOR = color.RGBA{R: 200, G: 255, B: 200, A: 255}
+var STOCK_EMPTY_COLOR = color.RGBA{R: 200, G: 40, B: 40, A: 255}
+
+// drawStockIndicator marks the empty draw pile with a ring when clicking it
+// turns the overturned pile back over, or a cross when no passes are left.
+func (b *Board) drawStockIndicator(screen *ebiten.Image) {
+	if b.drawPile == nil || b.overturnedPile == nil || len(b.drawPile.Cards) > 0 {
+		return
+	}
+	center := b.drawPile.basePos.Translate(cardWidth()/2, cardHeight()/2)
+	radius := float32(scaled(STOCK_INDICATOR_RADIUS))
+	strokeWidth := float32(scaled(STOCK_INDICATOR_STROKE_WIDTH))
+	if !b.state.OutOfPasses() {
+		vector.StrokeCircle(
+			screen,
+			float32(center.X),
+			float32(center.Y),
+			radius,
+			strokeWidth,
+			STOCK_RECYCLE_COLOR,
+			true,
+		)
+		return
+	}
+	for _, direction := range []float32{-1, 1} {
+		vector.StrokeLine(
+			screen,
+			float32(center.X)-radius,
+			float32(center.Y)-direction*radius,
+			float32(center.X)+radius,
+			float32(center.Y)+direction*radius,
+			strokeWidth,
+			STOCK_EMPTY_COLOR,
+			true,
+		)
+	}
+}
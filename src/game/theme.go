@@ -0,0 +1,242 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+// THEME_MANIFEST_NAME is the file in a theme's directory that describes it.
+const THEME_MANIFEST_NAME = "theme.json"
+
+// BUILTIN_THEMES_DIR holds the themes that come with the game, and
+// THEMES_DIR_NAME the player's own inside the config directory.
+const BUILTIN_THEMES_DIR = "assets/themes"
+const THEMES_DIR_NAME = "themes"
+const DEFAULT_THEME = "classic"
+
+// How the numbers on card faces are drawn: from the number images, or all
+// in the theme's font.
+const (
+	FACE_STYLE_CLASSIC = "classic"
+	FACE_STYLE_TEXT    = "text"
+)
+
+// How the base of an empty stack is drawn.
+const (
+	PLACEHOLDER_STYLE_FILLED  = "filled"
+	PLACEHOLDER_STYLE_OUTLINE = "outline"
+)
+
+const PLACEHOLDER_STROKE_WIDTH = 3
+
+// Theme is the look of the cards and the table, as described by a manifest.
+// Paths in the manifest are relative to the theme's directory.
+type Theme struct {
+	Name     string `json:"name"`
+	CardBack string `json:"cardBack"`
+	// CardBackTint is multiplied into the card back image, if it is set
+	CardBackTint ThemeColor `json:"cardBackTint"`
+	CardFace     string     `json:"cardFace"`
+	FaceStyle    string     `json:"faceStyle"`
+	// SuitColors are the colors of the suits, which default to red and black
+	SuitColors map[model.Suit]ThemeColor `json:"suitColors"`
+	Felt       ThemeColor                `json:"felt"`
+	// FeltTexture is tiled across the table and tinted with the felt color
+	FeltTexture      string     `json:"feltTexture"`
+	Placeholder      ThemeColor `json:"placeholder"`
+	PlaceholderStyle string     `json:"placeholderStyle"`
+	Font             string     `json:"font"`
+
+	dir string
+}
+
+// ThemeColor is a color written in a manifest as "#rrggbb" or "#rrggbbaa".
+type ThemeColor color.RGBA
+
+func (c *ThemeColor) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	var r, g, b, a uint8
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &r, &g, &b, &a); err != nil || len(hex) != 8 {
+		return fmt.Errorf("invalid color %q", s)
+	}
+	*c = ThemeColor{R: r, G: g, B: b, A: a}
+	return nil
+}
+
+func (c ThemeColor) Color() color.RGBA {
+	return color.RGBA(c)
+}
+
+// IsSet reports whether the color was given in the manifest.
+func (c ThemeColor) IsSet() bool {
+	return c != (ThemeColor{})
+}
+
+// LoadTheme reads the manifest of the theme in a directory.
+func LoadTheme(dir string) (*Theme, error) {
+	data, err := os.ReadFile(filepath.Join(dir, THEME_MANIFEST_NAME))
+	if err != nil {
+		return nil, err
+	}
+	t := &Theme{
+		FaceStyle:        FACE_STYLE_CLASSIC,
+		Felt:             ThemeColor{R: 0, G: 75, B: 0, A: 255},
+		Placeholder:      ThemeColor{R: 0, G: 150, B: 0, A: 255},
+		PlaceholderStyle: PLACEHOLDER_STYLE_FILLED,
+		dir:              dir,
+	}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	switch {
+	case t.Name == "":
+		return nil, fmt.Errorf("theme has no name")
+	case t.CardBack == "" || t.CardFace == "" || t.Font == "":
+		return nil, fmt.Errorf("theme %s needs a card back, a card face and a font", t.Name)
+	case t.FaceStyle != FACE_STYLE_CLASSIC && t.FaceStyle != FACE_STYLE_TEXT:
+		return nil, fmt.Errorf("theme %s has unknown face style %q", t.Name, t.FaceStyle)
+	case t.PlaceholderStyle != PLACEHOLDER_STYLE_FILLED && t.PlaceholderStyle != PLACEHOLDER_STYLE_OUTLINE:
+		return nil, fmt.Errorf("theme %s has unknown placeholder style %q", t.Name, t.PlaceholderStyle)
+	}
+	return t, nil
+}
+
+// LoadThemes reads every theme in the given directories, each theme being a
+// directory of its own. A theme replaces any earlier one of the same name,
+// so the player's themes can override the built-in ones.
+func LoadThemes(dirs ...string) []*Theme {
+	themes := []*Theme{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Println("Failed to read themes from", dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			theme, err := LoadTheme(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				log.Println("Skipping theme", entry.Name(), err)
+				continue
+			}
+			if i := FindTheme(themes, theme.Name); i >= 0 {
+				themes[i] = theme
+			} else {
+				themes = append(themes, theme)
+			}
+		}
+	}
+	return themes
+}
+
+// FindTheme returns the index of the theme with a name, or -1.
+func FindTheme(themes []*Theme, name string) int {
+	for i, theme := range themes {
+		if theme.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Theme) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(t.dir, name)
+}
+
+// suitColor returns the color a suit is drawn in.
+func (t *Theme) suitColor(suit model.Suit) color.RGBA {
+	if c, ok := t.SuitColors[suit]; ok {
+		return c.Color()
+	}
+	if suit.IsRed() {
+		return color.RGBA{R: 255, A: 255}
+	}
+	return color.RGBA{A: 255}
+}
+
+// activeTheme is the theme cards and the table are drawn with.
+var activeTheme *Theme = nil
+var feltTexture *ebiten.Image = nil
+
+// ApplyTheme loads the images and font of a theme and switches to it. The
+// current theme is kept if any of them fails to load. Boards render the
+// faces of their cards again on their next update.
+func ApplyTheme(theme *Theme) error {
+	reader, err := os.Open(theme.path(theme.Font))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	font, err := text.NewGoTextFaceSource(reader)
+	if err != nil {
+		return err
+	}
+	back, err := util.LoadEbitenImageFromFile(theme.path(theme.CardBack))
+	if err != nil {
+		return err
+	}
+	blank, err := util.LoadEbitenImageFromFile(theme.path(theme.CardFace))
+	if err != nil {
+		return err
+	}
+	var texture *ebiten.Image
+	if theme.FeltTexture != "" {
+		if texture, err = util.LoadEbitenImageFromFile(theme.path(theme.FeltTexture)); err != nil {
+			return err
+		}
+	}
+
+	activeTheme = theme
+	createTextfaces(font)
+	cardBackSource, cardBlankSource, feltTexture = back, blank, texture
+	renderCardImages()
+	log.Println("Using theme", theme.Name)
+	return nil
+}
+
+// ThemeName returns the name of the theme in use.
+func ThemeName() string {
+	return activeTheme.Name
+}
+
+// drawFelt covers the table in the felt of the theme.
+func drawFelt(screen *ebiten.Image) {
+	felt := activeTheme.Felt.Color()
+	if feltTexture == nil {
+		screen.Fill(felt)
+		return
+	}
+	size := feltTexture.Bounds().Size()
+	for y := 0; y < screen.Bounds().Dy(); y += size.Y {
+		for x := 0; x < screen.Bounds().Dx(); x += size.X {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(x), float64(y))
+			op.ColorScale.ScaleWithColor(felt)
+			screen.DrawImage(feltTexture, op)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"urffer.xyz/go-solitaire/src/animation"
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+const VICTORY_GRAVITY = 0.6
+const VICTORY_RESTITUTION = 0.8
+
+// victoryCascade is the classic celebration where the cards leave the
+// foundations one by one and bounce off the bottom of the screen, leaving a
+// trail of images behind them.
+type victoryCascade struct {
+	stacks     []*CardStack
+	nextStack  int
+	screenDims util.Dims
+
+	trail  *ebiten.Image
+	card   *Card
+	bounce *animation.Bounce
+}
+
+func newVictoryCascade(stacks []*CardStack, screenDims util.Dims) *victoryCascade {
+	return &victoryCascade{
+		stacks:     stacks,
+		screenDims: screenDims,
+		trail:      ebiten.NewImage(screenDims.X, screenDims.Y),
+	}
+}
+
+// launchNext sends the top card of the next non-empty stack bouncing, taking
+// the stacks in turn. It returns false once every stack is empty.
+func (v *victoryCascade) launchNext() bool {
+	for range v.stacks {
+		stack := v.stacks[v.nextStack]
+		v.nextStack = (v.nextStack + 1) % len(v.stacks)
+		if len(stack.Cards) == 0 {
+			continue
+		}
+
+		v.card = stack.Cards[len(stack.Cards)-1]
+		stack.Cards = stack.Cards[:len(stack.Cards)-1]
+		direction := 1.0
+		if rand.Intn(2) == 0 {
+			direction = -1.0
+		}
+		v.bounce = &animation.Bounce{
+			Pos: v.card.pos,
+			Velocity: util.Pos[float64]{
+				X: direction * scaled(2+rand.Float64()*5),
+				Y: -scaled(rand.Float64() * 8),
+			},
+			Gravity:     scaled(VICTORY_GRAVITY),
+			Floor:       float64(v.screenDims.Y) - cardHeight(),
+			Restitution: VICTORY_RESTITUTION,
+		}
+		return true
+	}
+	return false
+}
+
+func (v *victoryCascade) Update() {
+	if v.card == nil && !v.launchNext() {
+		return
+	}
+
+	// Move the card and stamp it onto the trail
+	v.bounce.Update()
+	v.card.pos = v.bounce.Pos
+	v.card.Draw(v.trail)
+
+	// Move on to the next card once this one has left the screen
+	if v.bounce.OutsideX(-cardWidth(), float64(v.screenDims.X)) {
+		v.card = nil
+	}
+}
+
+func (v *victoryCascade) Done() bool {
+	if v.card != nil {
+		return false
+	}
+	for _, stack := range v.stacks {
+		if len(stack.Cards) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Skip drops the remaining cards so that the victory panel shows straight away.
+func (v *victoryCascade) Skip() {
+	for _, stack := range v.stacks {
+		stack.Cards = nil
+	}
+	v.card = nil
+}
+
+func (v *victoryCascade) Draw(screen *ebiten.Image) {
+	screen.DrawImage(v.trail, &ebiten.DrawImageOptions{})
+}
+
+func (b *Board) victoryLines() []string {
+	minutes, seconds := splitMinutes(b.Elapsed())
+	return []string{
+		"You won!",
+		fmt.Sprintf("Time: %d:%02d", minutes, seconds),
+		fmt.Sprintf("Moves: %d", len(b.history.Steps())),
+		fmt.Sprintf("Score: %d", b.finalScore),
+		"",
+		"Click or press N or Enter for a new game",
+	}
+}
+
+func (b *Board) drawVictoryPanel(screen *ebiten.Image) {
+	DrawPanel(screen, b.victoryLines())
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/solver"
+)
+
+// CheckWinnable asks the solver, in the background, whether the game can
+// still be won from the current position.
+func (b *Board) CheckWinnable() {
+	if b.Busy() || b.solverResults != nil {
+		return
+	}
+	if b.state.Options.Game != model.GameKlondike {
+		log.Println("The solver can only check Klondike games")
+		return
+	}
+	log.Println("Checking whether the game is winnable")
+	results := make(chan solver.Result, 1)
+	done := make(chan struct{})
+	state := b.state.Clone()
+	go func() {
+		results <- solver.Solve(state, solver.DefaultOptions, done)
+	}()
+	b.solverResults = results
+	b.solverDone = done
+	b.solverResult = nil
+}
+
+// pollWinnable collects the solver's answer once it is ready.
+func (b *Board) pollWinnable() {
+	if b.solverResults == nil {
+		return
+	}
+	select {
+	case result := <-b.solverResults:
+		log.Println("Solver finished:", result.Status, "after", result.Nodes, "nodes in", result.Duration)
+		b.solverResult = &result
+		b.solverResults = nil
+		b.solverDone = nil
+	default:
+	}
+}
+
+// clearWinnable forgets the solver's answer once the position has changed,
+// cancelling a search that is still running.
+func (b *Board) clearWinnable() {
+	if b.solverDone != nil {
+		close(b.solverDone)
+		b.solverDone = nil
+	}
+	b.solverResults = nil
+	b.solverResult = nil
+}
+
+func (b *Board) winnableHudItem() (string, bool) {
+	if b.solverResults != nil {
+		return "Checking if winnable...", true
+	}
+	if b.solverResult == nil {
+		return "", false
+	}
+	switch b.solverResult.Status {
+	case solver.Solvable:
+		return fmt.Sprintf("Winnable in %d moves", len(b.solverResult.Moves)), true
+	case solver.Unsolvable:
+		return "Not winnable", true
+	default:
+		return "Winnable: unknown", true
+	}
+}
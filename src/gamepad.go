@@ -0,0 +1,146 @@
+package main
+
+import (
+	"log"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"urffer.xyz/go-solitaire/src/game"
+)
+
+const GAMEPAD_STICK_THRESHOLD = 0.5
+
+// GAMEPAD_REPEAT_DELAY is how many ticks a direction is held before it starts
+// repeating, and GAMEPAD_REPEAT_INTERVAL how often it repeats after that.
+const GAMEPAD_REPEAT_DELAY = 20
+const GAMEPAD_REPEAT_INTERVAL = 6
+
+// The face buttons of the standard layout, named as on an Xbox controller.
+const (
+	GAMEPAD_A     = ebiten.StandardGamepadButtonRightBottom
+	GAMEPAD_B     = ebiten.StandardGamepadButtonRightRight
+	GAMEPAD_X     = ebiten.StandardGamepadButtonRightLeft
+	GAMEPAD_Y     = ebiten.StandardGamepadButtonRightTop
+	GAMEPAD_START = ebiten.StandardGamepadButtonCenterRight
+	GAMEPAD_BACK  = ebiten.StandardGamepadButtonCenterLeft
+)
+
+var GAMEPAD_DIRECTIONS = map[ebiten.StandardGamepadButton]game.Direction{
+	ebiten.StandardGamepadButtonLeftTop:    game.DirUp,
+	ebiten.StandardGamepadButtonLeftBottom: game.DirDown,
+	ebiten.StandardGamepadButtonLeftLeft:   game.DirLeft,
+	ebiten.StandardGamepadButtonLeftRight:  game.DirRight,
+}
+
+// gamepads tracks the connected gamepads that have the standard layout, and
+// which way any of them is being pushed.
+type gamepads struct {
+	ids []ebiten.GamepadID
+
+	held      game.Direction
+	holding   bool
+	heldTicks int
+}
+
+// update picks up gamepads as they are plugged in and out, and follows the
+// direction being pushed. It must be called once every tick.
+func (p *gamepads) update() {
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			log.Printf("Ignoring gamepad %q, it has no standard layout", ebiten.GamepadName(id))
+			continue
+		}
+		log.Printf("Gamepad %q connected", ebiten.GamepadName(id))
+		p.ids = append(p.ids, id)
+	}
+	p.ids = slices.DeleteFunc(p.ids, func(id ebiten.GamepadID) bool {
+		if inpututil.IsGamepadJustDisconnected(id) {
+			log.Println("Gamepad disconnected")
+			return true
+		}
+		return false
+	})
+
+	dir, ok := p.pushed()
+	switch {
+	case !ok:
+		p.holding = false
+	case !p.holding || dir != p.held:
+		p.held, p.holding, p.heldTicks = dir, true, 0
+	default:
+		p.heldTicks++
+	}
+}
+
+// pushed returns the direction the D-pad or the left stick of any gamepad is
+// pushed in.
+func (p *gamepads) pushed() (game.Direction, bool) {
+	for _, id := range p.ids {
+		for button, dir := range GAMEPAD_DIRECTIONS {
+			if ebiten.IsStandardGamepadButtonPressed(id, button) {
+				return dir, true
+			}
+		}
+
+		x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+		y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+		switch {
+		case max(math.Abs(x), math.Abs(y)) < GAMEPAD_STICK_THRESHOLD:
+			continue
+		case math.Abs(x) > math.Abs(y) && x < 0:
+			return game.DirLeft, true
+		case math.Abs(x) > math.Abs(y):
+			return game.DirRight, true
+		case y < 0:
+			return game.DirUp, true
+		default:
+			return game.DirDown, true
+		}
+	}
+	return 0, false
+}
+
+// direction reports the direction being pushed on the tick it is first
+// pushed, and then repeatedly for as long as it is held.
+func (p *gamepads) direction() (game.Direction, bool) {
+	if !p.holding {
+		return 0, false
+	}
+	repeat := p.heldTicks >= GAMEPAD_REPEAT_DELAY && (p.heldTicks-GAMEPAD_REPEAT_DELAY)%GAMEPAD_REPEAT_INTERVAL == 0
+	return p.held, p.heldTicks == 0 || repeat
+}
+
+// justPressed reports whether a button was pressed on any gamepad this tick.
+func (p *gamepads) justPressed(button ebiten.StandardGamepadButton) bool {
+	for _, id := range p.ids {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateGamepad plays the game from a gamepad: the D-pad or stick moves the
+// focus, A picks cards up and drops them, B puts held cards back, X draws, Y
+// shows a hint, Start opens the menu and Back the settings.
+func (g *Game) updateGamepad() {
+	if dir, ok := g.pads.direction(); ok {
+		g.board.MoveFocus(dir)
+	}
+	switch {
+	case g.pads.justPressed(GAMEPAD_A):
+		g.board.Activate()
+	case g.pads.justPressed(GAMEPAD_B):
+		g.board.CancelHeld()
+	case g.pads.justPressed(GAMEPAD_X):
+		g.board.DrawFromStock()
+	case g.pads.justPressed(GAMEPAD_Y):
+		g.board.NextHint()
+	case g.pads.justPressed(GAMEPAD_START):
+		g.menu = game.NewMenu(g.board.State().Options)
+	case g.pads.justPressed(GAMEPAD_BACK):
+		g.settingsMenu = game.NewSettingsMenu(g.themes)
+	}
+}
@@ -1,61 +1,656 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
 	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"urffer.xyz/go-solitaire/src/deals"
 	"urffer.xyz/go-solitaire/src/game"
+	"urffer.xyz/go-solitaire/src/model"
+	"urffer.xyz/go-solitaire/src/scoring"
+	"urffer.xyz/go-solitaire/src/stats"
 	"urffer.xyz/go-solitaire/src/util"
 )
 
+// DOUBLE_CLICK_TICKS is the longest gap between the clicks of a double click.
+const DOUBLE_CLICK_TICKS = 30
+const DOUBLE_CLICK_SLOP = 5
+
+// The smallest window the board is still playable in
+const MIN_WINDOW_WIDTH = 400
+const MIN_WINDOW_HEIGHT = 320
+
 type Game struct {
 	windowSize       util.Dims
 	windowRenderDims util.Dims
+	// deviceScale is how many screen pixels make up a logical pixel of the window
+	deviceScale float64
+
+	options    model.Options
+	scorer     scoring.Scorer
+	autoFinish bool
+	showHud    bool
+	// passes is the limit on passes through the stock asked for on the
+	// command line, or -1 to play with the scoring system's own
+	passes int
+	// winnable restricts Klondike to deals the solver has shown to be winnable
+	winnable bool
+	// dealPool supplies verified winnable deals when only those are wanted
+	dealPool *deals.Pool
+	board    *game.Board
+
+	// savePath is where the game in progress is saved on exit
+	savePath     string
+	resumePrompt bool
+
+	// recordingName is the file the current game is recorded to
+	recordingName string
+	// winRecorded is set once a won game has been recorded and counted
+	winRecorded bool
+
+	// stats is nil if the statistics could not be loaded
+	stats     *stats.Store
+	showStats bool
+
+	// menu is set while the player is choosing the next game
+	menu *game.Menu
+
+	// themes are the looks that can be chosen from the settings menu, which
+	// is set while it is open
+	themes       []*game.Theme
+	settingsMenu *game.SettingsMenu
+	// settings is nil if the settings could not be loaded
+	settings *game.Settings
+
+	// replay is set when playing back a recording instead of playing
+	replay *game.Replay
 
-	board *game.Board
+	ticks         int
+	lastClickTick int
+	lastClickPos  util.Pos[int]
+	pads          gamepads
+	touch         touchTracker
+	// lastCursorPos is where the mouse was last seen, so the keyboard keeps
+	// control of the cursor until the mouse is used again
+	lastCursorPos util.Pos[int]
 }
 
 func (g *Game) Init() {
 	// Set the window size and title
-	ebiten.SetWindowTitle("Solitaire")
+	g.updateTitle()
 	ebiten.SetWindowSize(g.windowSize.X, g.windowSize.Y)
+
+	// The board scales to fit whatever size the window is given
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowSizeLimits(MIN_WINDOW_WIDTH, MIN_WINDOW_HEIGHT, -1, -1)
+
+	// Save the game when the window is closed
+	ebiten.SetWindowClosingHandled(true)
+}
+
+func (g *Game) updateTitle() {
+	ebiten.SetWindowTitle(fmt.Sprintf("Solitaire - %s - Seed %d", g.board.State().Options.Name(), g.board.Seed()))
+}
+
+func (g *Game) NewGame() {
+	// A game given up on after making moves counts as a loss, while in Vegas
+	// scoring even an untouched game costs its buy-in
+	g.board.Abandon()
+	if !g.board.Won() && g.board.Moves() > 0 {
+		g.recordStats(false)
+	} else if g.keepBankroll() {
+		g.saveStats()
+	}
+	g.startBoard(g.nextSeed())
+}
+
+// applyTheme switches to a theme from the settings menu and remembers the
+// choice for the next run.
+func (g *Game) applyTheme(theme *game.Theme) {
+	if err := game.ApplyTheme(theme); err != nil {
+		log.Println("Failed to load theme", theme.Name, err)
+		return
+	}
+	if g.settings == nil {
+		return
+	}
+	g.settings.Theme = theme.Name
+	if err := g.settings.Save(); err != nil {
+		log.Println("Failed to save settings:", err)
+	}
+}
+
+// switchGame starts a new game with different options.
+func (g *Game) switchGame(opts model.Options) {
+	g.setOptions(opts)
+	g.NewGame()
+}
+
+// setOptions sets the rules later games are dealt with, keeping a pool of
+// winnable deals going only for games the solver understands.
+func (g *Game) setOptions(opts model.Options) {
+	g.closeDealPool()
+	g.options = g.withPasses(opts)
+	if g.winnable && opts.Game == model.GameKlondike {
+		g.dealPool = deals.NewPool(g.options, deals.DEFAULT_POOL_SIZE)
+	}
+}
+
+// withPasses sets the limit on passes through the stock the game is played with.
+func (g *Game) withPasses(opts model.Options) model.Options {
+	opts.Passes = g.passes
+	if g.passes < 0 {
+		opts.Passes = g.scorer.Passes(opts)
+	}
+	return opts
+}
+
+func (g *Game) closeDealPool() {
+	if g.dealPool != nil {
+		g.dealPool.Close()
+		g.dealPool = nil
+	}
+}
+
+func (g *Game) recordStats(won bool) {
+	if g.stats == nil {
+		return
+	}
+	// Scores of different scoring systems can't be compared, so each keeps its own records
+	category := fmt.Sprintf("%s, %s scoring", g.board.State().Options.Name(), g.board.Scorer().Name())
+	if won {
+		g.stats.RecordWin(category, g.board.Elapsed(), g.board.Moves(), g.board.Score())
+	} else {
+		g.stats.RecordLoss(category)
+	}
+	g.keepBankroll()
+	g.saveStats()
+}
+
+func (g *Game) saveStats() {
+	if err := g.stats.Save(); err != nil {
+		log.Println("Failed to save statistics:", err)
+	}
+}
+
+// keepBankroll notes the cumulative Vegas bankroll in the statistics so
+// that it carries over to the next session, reporting whether there is one.
+func (g *Game) keepBankroll() bool {
+	vegas, ok := g.scorer.(*scoring.Vegas)
+	if g.stats == nil || !ok || !vegas.Cumulative {
+		return false
+	}
+	g.stats.Bankroll = vegas.Bankroll()
+	return true
+}
+
+// nextSeed picks the seed of the next deal, taking a verified winnable one
+// from the pool if only those are wanted. Rather than keep the player
+// waiting, a random deal is dealt while the pool is still searching.
+func (g *Game) nextSeed() (uint64, bool) {
+	if g.dealPool != nil {
+		if deal, ok := g.dealPool.Next(); ok {
+			return deal.Seed, true
+		}
+		log.Println("No winnable deal found yet, dealing an unverified one")
+	}
+	return g.options.Game.RandomSeed(), false
+}
+
+func (g *Game) startBoard(seed uint64, verifiedWinnable bool) {
+	g.setBoard(game.NewBoard(seed, g.options, g.scorer))
+	if verifiedWinnable {
+		g.board.MarkVerifiedWinnable()
+	}
+}
+
+func (g *Game) setBoard(board *game.Board) {
+	g.board = board
+	g.board.SetScreenDims(g.windowRenderDims)
+	g.board.SetAutoFinish(g.autoFinish)
+	g.board.SetShowHud(g.showHud)
+	g.updateTitle()
+
+	g.recordingName = fmt.Sprintf("%s-%d.json", time.Now().Format("20060102-150405"), board.Seed())
+	g.winRecorded = board.Won()
+}
+
+// saveRecording writes the moves of the current game to the recordings directory.
+func (g *Game) saveRecording() {
+	path, err := util.ConfigPath(filepath.Join(game.RECORDINGS_DIR_NAME, g.recordingName))
+	if err != nil {
+		log.Println("Failed to save recording:", err)
+		return
+	}
+	if err := game.SaveRecording(path, g.board.Recording()); err != nil {
+		log.Println("Failed to save recording:", err)
+		return
+	}
+	log.Println("Game recorded to", path)
+}
+
+// loadSavedGame offers to resume the game saved on the last exit, if any.
+func (g *Game) loadSavedGame() bool {
+	if g.savePath == "" {
+		return false
+	}
+	board, err := game.LoadBoard(g.savePath, g.scorer)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Failed to load saved game:", err)
+		}
+		return false
+	}
+	// Later games are played and scored like the resumed one, with the same scorer
+	g.scorer = board.Scorer()
+	g.setOptions(board.State().Options)
+	g.setBoard(board)
+	g.resumePrompt = true
+	return true
+}
+
+// saveGame saves the game in progress, or removes the old save once there is
+// nothing left to resume.
+func (g *Game) saveGame() {
+	if g.savePath == "" {
+		return
+	}
+	if g.board.Won() {
+		if err := os.Remove(g.savePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Println("Failed to remove saved game:", err)
+		}
+		return
+	}
+	if err := g.board.Save(g.savePath); err != nil {
+		log.Println("Failed to save game:", err)
+	}
 }
 
 func (g *Game) Update() error {
+	g.ticks++
+	g.pads.update()
+
+	// Save the game and quit when the window is closed
+	if ebiten.IsWindowBeingClosed() {
+		g.saveGame()
+		return ebiten.Termination
+	}
+
+	// Playing back a recording takes over all input
+	if g.replay != nil {
+		g.replay.Update()
+		return nil
+	}
+
+	// Show the statistics over the paused game until they are closed
+	if g.showStats {
+		if inpututil.IsKeyJustPressed(ebiten.KeyDelete) {
+			g.stats.Reset()
+			g.saveStats()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyS) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) || g.pads.justPressed(GAMEPAD_B) {
+			g.showStats = false
+		}
+		return nil
+	}
+
+	// Let the player pick the next game from the menu until it is closed
+	if g.menu != nil {
+		dir, pushed := g.pads.direction()
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyUp) || (pushed && dir == game.DirUp):
+			g.menu.Prev()
+		case inpututil.IsKeyJustPressed(ebiten.KeyDown) || (pushed && dir == game.DirDown):
+			g.menu.Next()
+		case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || g.pads.justPressed(GAMEPAD_A):
+			choice := g.menu.Selected()
+			g.menu = nil
+			log.Println("Switching to", choice.Label)
+			g.switchGame(choice.Options)
+		case inpututil.IsKeyJustPressed(ebiten.KeyM) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+			g.pads.justPressed(GAMEPAD_B) || g.pads.justPressed(GAMEPAD_START):
+			g.menu = nil
+		}
+		return nil
+	}
+
+	// Let the player change the settings until they are closed
+	if g.settingsMenu != nil {
+		dir, pushed := g.pads.direction()
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyUp) || (pushed && dir == game.DirUp):
+			g.settingsMenu.Prev()
+		case inpututil.IsKeyJustPressed(ebiten.KeyDown) || (pushed && dir == game.DirDown):
+			g.settingsMenu.Next()
+		case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || g.pads.justPressed(GAMEPAD_A):
+			g.applyTheme(g.settingsMenu.Selected())
+		case inpututil.IsKeyJustPressed(ebiten.KeyO) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+			g.pads.justPressed(GAMEPAD_B) || g.pads.justPressed(GAMEPAD_BACK):
+			g.settingsMenu = nil
+		}
+		return nil
+	}
+
+	// Wait for the player to decide whether to resume the saved game
+	if g.resumePrompt {
+		if inpututil.IsKeyJustPressed(ebiten.KeyY) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) || g.pads.justPressed(GAMEPAD_A) {
+			g.resumePrompt = false
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyN) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) || g.pads.justPressed(GAMEPAD_B) {
+			g.resumePrompt = false
+			g.NewGame()
+		}
+		return nil
+	}
+
 	// Update the game board with any non-interactive logic
 	g.board.Update()
 
-	// Handle mouse input
+	// Keep a recording of every won game and count it in the statistics
+	if g.board.Won() && !g.winRecorded {
+		g.winRecorded = true
+		g.saveRecording()
+		g.recordStats(true)
+	}
+
+	// Once a won game has finished celebrating, wait for the player to start another
+	if g.board.ReadyForNewGame() {
+		if inpututil.IsKeyJustPressed(ebiten.KeyN) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
+			inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || g.pads.justPressed(GAMEPAD_A) ||
+			len(inpututil.AppendJustPressedTouchIDs(nil)) > 0 {
+			g.NewGame()
+		}
+		return nil
+	}
+
+	// Handle touch input, which takes over from the mouse while a finger is down
+	if !g.updateTouch() {
+		g.updateMouse()
+	}
+
+	// Handle playing with the keyboard or a gamepad
+	g.updateKeyboard()
+	g.updateGamepad()
+
+	// Handle showing the next hint
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
+		g.board.NextHint()
+	}
+
+	// Handle checking whether the game can still be won
+	if inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		g.board.CheckWinnable()
+	}
+
+	// Handle finishing the game automatically
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		g.board.AutoFinish()
+	}
+
+	// Handle opening the game menu
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		g.menu = game.NewMenu(g.board.State().Options)
+	}
+
+	// Handle opening the settings menu
+	if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+		g.settingsMenu = game.NewSettingsMenu(g.themes)
+	}
+
+	// Handle showing and hiding the heads-up display
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		g.showHud = !g.showHud
+		g.board.SetShowHud(g.showHud)
+	}
+
+	// Handle undo (Ctrl+Z), redo (Ctrl+Y) and recording the game (Ctrl+S)
+	if ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta) {
+		if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+			g.board.Undo()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyY) {
+			g.board.Redo()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+			g.saveRecording()
+		}
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyS) && g.stats != nil {
+		g.showStats = true
+	}
+
+	return nil
+}
+
+// updateMouse handles the mouse, leaving the cursor to the keyboard until the
+// mouse is moved or clicked.
+func (g *Game) updateMouse() {
 	pos := util.MakePosFromTuple(ebiten.CursorPosition())
+	if pos == g.lastCursorPos && !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) &&
+		!inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		return
+	}
+	g.lastCursorPos = pos
 	g.board.SetCusrorPos(pos)
+	game.SetTouchInput(false)
+
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		g.board.MouseDown()
+		// A double click sends a card home, or acts as a normal click where there is no card
+		if !g.isDoubleClick(pos) || !g.board.AutoMove() {
+			g.board.MouseDown()
+		}
 	} else if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
 		g.board.MouseUp()
+	} else if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		g.board.AutoMove()
 	}
+}
 
-	return nil
+// FOCUS_KEYS maps the arrow keys to the directions they move the focus in.
+var FOCUS_KEYS = map[ebiten.Key]game.Direction{
+	ebiten.KeyArrowUp:    game.DirUp,
+	ebiten.KeyArrowDown:  game.DirDown,
+	ebiten.KeyArrowLeft:  game.DirLeft,
+	ebiten.KeyArrowRight: game.DirRight,
+}
+
+// updateKeyboard lets the game be played without a mouse: the arrow keys
+// move the focus, Enter or Space picks cards up and drops them, D draws,
+// Tab sends the next card home and Escape puts held cards back.
+func (g *Game) updateKeyboard() {
+	for key, dir := range FOCUS_KEYS {
+		if inpututil.IsKeyJustPressed(key) {
+			g.board.MoveFocus(dir)
+		}
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		g.board.Activate()
+	case inpututil.IsKeyJustPressed(ebiten.KeyD):
+		g.board.DrawFromStock()
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		g.board.CycleFoundationMove()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.board.CancelHeld()
+	}
+}
+
+// isDoubleClick records a click and reports whether it completes a double
+// click. The click after a double click starts counting again.
+func (g *Game) isDoubleClick(pos util.Pos[int]) bool {
+	delta := pos.Sub(g.lastClickPos)
+	isDouble := g.lastClickTick > 0 &&
+		g.ticks-g.lastClickTick <= DOUBLE_CLICK_TICKS &&
+		util.Pos[float64]{}.AlmostEq(delta.ToFloatPos(), DOUBLE_CLICK_SLOP*g.deviceScale)
+	if isDouble {
+		g.lastClickTick = 0
+	} else {
+		g.lastClickTick = g.ticks
+		g.lastClickPos = pos
+	}
+	return isDouble
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
+	if g.replay != nil {
+		g.replay.Draw(screen)
+		return
+	}
 	g.board.Draw(screen)
+
+	if g.showStats {
+		game.DrawStats(screen, g.stats)
+	}
+	if g.menu != nil {
+		g.menu.Draw(screen)
+	}
+	if g.settingsMenu != nil {
+		g.settingsMenu.Draw(screen)
+	}
+	if g.resumePrompt {
+		minutes := int(g.board.Elapsed().Minutes())
+		seconds := int(g.board.Elapsed().Seconds()) % 60
+		game.DrawPanel(screen, []string{
+			"Resume last game?",
+			fmt.Sprintf("Seed %d, %d moves, %d:%02d", g.board.Seed(), g.board.Moves(), minutes, seconds),
+			"",
+			"Press Y to resume or N for a new game",
+		})
+	}
 }
 
+// Layout renders at the size of the window in screen pixels, fitting the
+// board to it, so that nothing is upscaled on high density displays.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	g.deviceScale = ebiten.Monitor().DeviceScaleFactor()
+	game.SetDeviceScale(g.deviceScale)
+	g.windowRenderDims = util.Dims{
+		X: int(math.Ceil(float64(outsideWidth) * g.deviceScale)),
+		Y: int(math.Ceil(float64(outsideHeight) * g.deviceScale)),
+	}
+	g.board.SetScreenDims(g.windowRenderDims)
 	return g.windowRenderDims.X, g.windowRenderDims.Y
 }
 
 func main() {
+	// Parse the command-line options
+	opts := model.DefaultOptions
+	gameName := flag.String("game", opts.Game.String(), "game to play: klondike, freecell, spider, yukon, golf, pyramid or tripeaks; chosen in game with M")
+	flag.IntVar(&opts.DrawCount, "draw", opts.DrawCount, "number of cards turned from the stock at once (1 or 3)")
+	flag.IntVar(&opts.Suits, "suits", opts.Suits, "number of suits Spider is played with (1, 2 or 4)")
+	passes := flag.Int("passes", -1, "number of passes allowed through the stock, 0 for no limit; defaults to the scoring system's (1 or 3 in Vegas, unlimited otherwise)")
+	seed := flag.Uint64("seed", 0, "seed of the deal to play, or the Microsoft deal number in FreeCell; a random deal is chosen if omitted")
+	scoringName := flag.String("scoring", "standard", "scoring system: standard, vegas or vegas-cumulative")
+	autoFinish := flag.Bool("auto-finish", false, "finish trivially solved games automatically")
+	showHud := flag.Bool("hud", true, "show the time, moves, score and seed; toggled in game with T")
+	winnable := flag.Bool("winnable", false, "deal games the solver has shown to be winnable, or a random deal while none has been found yet")
+	replayPath := flag.String("replay", "", "play back a recorded game instead of playing")
+	themeName := flag.String("theme", "", "name of the theme to play with; defaults to the one last chosen in game with O")
+	flag.Parse()
+	seedSet := false
+	flag.Visit(func(f *flag.Flag) {
+		seedSet = seedSet || f.Name == "seed"
+	})
+	scorer := scoring.New(*scoringName)
+	if scorer == nil {
+		log.Fatalf("Unknown scoring system %q", *scoringName)
+	}
+	var ok bool
+	if opts.Game, ok = model.ParseGame(*gameName); !ok {
+		log.Fatalf("Unknown game %q", *gameName)
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if err := opts.Game.CheckSeed(*seed); seedSet && err != nil {
+		log.Fatal(err)
+	}
+	if *passes < -1 {
+		log.Fatalf("Invalid number of passes %d", *passes)
+	}
+	if *winnable && opts.Game != model.GameKlondike {
+		log.Fatalf("Only Klondike deals can be checked for being winnable")
+	}
+
+	// Load the settings and the built-in and the player's own themes
+	var settings *game.Settings
+	if settingsPath, err := util.ConfigPath(game.SETTINGS_FILE_NAME); err != nil {
+		log.Println("Settings will not be kept:", err)
+	} else if settings, err = game.LoadSettings(settingsPath); err != nil {
+		log.Println("Settings will not be kept:", err)
+	}
+	themeDirs := []string{game.BUILTIN_THEMES_DIR}
+	if userThemesDir, err := util.ConfigPath(game.THEMES_DIR_NAME); err == nil {
+		themeDirs = append(themeDirs, userThemesDir)
+	}
+	themes := game.LoadThemes(themeDirs...)
+	if *themeName == "" {
+		*themeName = game.DEFAULT_THEME
+		if settings != nil {
+			*themeName = settings.Theme
+		}
+	}
+	themeIndex := game.FindTheme(themes, *themeName)
+	if themeIndex < 0 {
+		log.Println("Unknown theme", *themeName, "using", game.DEFAULT_THEME)
+		themeIndex = game.FindTheme(themes, game.DEFAULT_THEME)
+	}
+	if themeIndex < 0 {
+		log.Fatalf("No theme found in %s", game.BUILTIN_THEMES_DIR)
+	}
+
 	// Initialize the game assets
-	game.InitCardsAssets()
-	game.InitCardStackBkg()
+	game.InitCardsAssets(themes[themeIndex])
 
 	// Create the game instance, init, and run it
 	ebitengineGame := &Game{
-		windowSize:       util.Dims{X: 1000, Y: 800},
-		windowRenderDims: util.Dims{X: 1000, Y: 800},
-		board:            game.NewBoard(),
+		themes:           themes,
+		settings:         settings,
+		windowSize:       game.MinScreenDims(opts),
+		windowRenderDims: game.MinScreenDims(opts),
+		deviceScale:      1,
+		scorer:           scorer,
+		autoFinish:       *autoFinish,
+		showHud:          *showHud,
+		passes:           *passes,
+		winnable:         *winnable,
+	}
+	ebitengineGame.setOptions(opts)
+	defer ebitengineGame.closeDealPool()
+	// Replays are only watched, so there is nothing to save or count
+	if *replayPath == "" {
+		if savePath, err := util.ConfigPath(game.SAVE_FILE_NAME); err != nil {
+			log.Println("Games will not be saved:", err)
+		} else {
+			ebitengineGame.savePath = savePath
+		}
+		if statsPath, err := util.ConfigPath(stats.STATS_FILE_NAME); err != nil {
+			log.Println("Statistics will not be kept:", err)
+		} else if ebitengineGame.stats, err = stats.Load(statsPath); err != nil {
+			log.Println("Statistics will not be kept:", err)
+		}
+		if vegas, ok := scorer.(*scoring.Vegas); ok && ebitengineGame.stats != nil {
+			vegas.SetBankroll(ebitengineGame.stats.Bankroll)
+		}
+	}
+
+	// Start by playing back a recording, the requested deal, the saved game or a new deal
+	switch {
+	case *replayPath != "":
+		recording, err := game.LoadRecording(*replayPath)
+		if err != nil {
+			log.Fatalf("Failed to load recording %s: %v", *replayPath, err)
+		}
+		ebitengineGame.replay = game.NewReplay(recording)
+		ebitengineGame.setBoard(ebitengineGame.replay.Board())
+	case seedSet:
+		ebitengineGame.startBoard(*seed, false)
+	case !ebitengineGame.loadSavedGame():
+		ebitengineGame.startBoard(ebitengineGame.nextSeed())
 	}
 	ebitengineGame.Init()
 	if err := ebiten.RunGame(ebitengineGame); err != nil {
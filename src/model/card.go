@@ -0,0 +1,25 @@
+package model
+
+// CardID identifies a physical card independently of its face-up state.
+type CardID struct {
+	Number Number
+	Suit   Suit
+	Deck   int
+}
+
+type Card struct {
+	Number Number
+	Suit   Suit
+	// Deck tells apart the copies of a card in games played with more than
+	// one deck. It is zero in single deck games.
+	Deck   int
+	FaceUp bool
+}
+
+func (c Card) ID() CardID {
+	return CardID{Number: c.Number, Suit: c.Suit, Deck: c.Deck}
+}
+
+func (c Card) String() string {
+	return NumberSymbols[c.Number] + SuitSymbols[c.Suit]
+}
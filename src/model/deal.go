@@ -0,0 +1,60 @@
+package model
+
+// NewDeck returns the 52 cards of a standard deck, face down, in suit order.
+func NewDeck() []Card {
+	deck := make([]Card, 0, len(Suits)*len(Numbers))
+	for _, suit := range Suits {
+		for _, number := range Numbers {
+			deck = append(deck, Card{Number: number, Suit: suit})
+		}
+	}
+	return deck
+}
+
+// Shuffle performs a Fisher-Yates shuffle driven by the given generator.
+func Shuffle(cards []Card, rng *Rand) {
+	for i := len(cards) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+// NewState sets up the empty piles of a game.
+func NewState(opts Options) *State {
+	layout := opts.Variant().Layout(opts)
+	return &State{
+		Options:     opts,
+		Cells:       make([]Pile, layout.Cells),
+		Foundations: make([]Pile, layout.Foundations),
+		Tableau:     make([]Pile, layout.Tableau),
+	}
+}
+
+// Deal lays out a game from the given deck, following the rules of the
+// variant in the options.
+func Deal(deck []Card, opts Options) *State {
+	cards := make([]Card, len(deck))
+	copy(cards, deck)
+	setFaceUp(cards, false)
+
+	state := NewState(opts)
+	opts.Variant().Deal(state, cards)
+	return state
+}
+
+// NewGame deals a deck shuffled from the given seed. The same seed always
+// yields the same layout. Variants with numbered deals take the seed as the
+// deal number instead.
+func NewGame(seed uint64, opts Options) *State {
+	variant := opts.Variant()
+	var deck []Card
+	if numbered, ok := variant.(NumberedDeals); ok {
+		deck = numbered.NumberedDeck(seed)
+	} else {
+		deck = variant.Deck(opts)
+		Shuffle(deck, NewRand(seed))
+	}
+	state := Deal(deck, opts)
+	state.Seed = seed
+	return state
+}
@@ -0,0 +1,76 @@
+package model
+
+// CanAutoFinish reports whether the game can be finished without any more
+// decisions, by sending cards to the foundations one at a time.
+func (s *State) CanAutoFinish() bool {
+	return s.Options.Variant().CanAutoFinish(s)
+}
+
+// NextFoundationMove finds a card that can be played to a foundation,
+// preferring the lowest one so that no foundation gets ahead of the others.
+func (s *State) NextFoundationMove() (Move, bool) {
+	best, found := Move{}, false
+	var bestNumber Number
+	for _, from := range s.PileIDs() {
+		if from.Kind == PileFoundation {
+			continue
+		}
+		top, ok := s.Pile(from).Top()
+		if !ok || !top.FaceUp || (found && top.Number >= bestNumber) {
+			continue
+		}
+		for i := range s.Foundations {
+			if m := CardsMove(from, FoundationID(i), 1); s.Validate(m) == nil {
+				best, found, bestNumber = m, true, top.Number
+				break
+			}
+		}
+	}
+	return best, found
+}
+
+// BestMove picks a destination for the top count cards of a pile: a
+// foundation if one accepts them, then the waste, otherwise the first
+// tableau column that does, trying columns whose top card is of the same
+// suit first and empty columns last, and finally a free cell.
+func (s *State) BestMove(from PileID, count int) (Move, bool) {
+	for i := range s.Foundations {
+		if m := CardsMove(from, FoundationID(i), count); s.Validate(m) == nil {
+			return m, true
+		}
+	}
+	if m := CardsMove(from, WasteID, count); s.Validate(m) == nil {
+		return m, true
+	}
+	pile := s.Pile(from)
+	if pile == nil || count < 1 || count > pile.Len() {
+		return Move{}, false
+	}
+	suit := pile.Cards[pile.Len()-count].Suit
+	preferences := []func(column *Pile) bool{
+		func(column *Pile) bool {
+			top, ok := column.Top()
+			return ok && top.Suit == suit
+		},
+		func(column *Pile) bool { return !column.IsEmpty() },
+		func(column *Pile) bool { return column.IsEmpty() },
+	}
+	for _, preferred := range preferences {
+		for i := range s.Tableau {
+			if !preferred(&s.Tableau[i]) {
+				continue
+			}
+			if m := CardsMove(from, TableauID(i), count); s.Validate(m) == nil {
+				return m, true
+			}
+		}
+	}
+	if from.Kind != PileCell {
+		for i := range s.Cells {
+			if m := CardsMove(from, CellID(i), count); s.Validate(m) == nil {
+				return m, true
+			}
+		}
+	}
+	return Move{}, false
+}
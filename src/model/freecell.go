@@ -0,0 +1,113 @@
+package model
+
+// NumFreeCellTableau is the number of columns FreeCell deals the whole deck
+// into, as it has no stock.
+const NumFreeCellTableau = 8
+
+// NumFreeCells is the number of cells single cards can be parked in.
+const NumFreeCells = 4
+
+// FreeCell deals every card face up, so nearly every deal can be won by
+// careful use of the free cells. Deals are numbered as in Microsoft FreeCell.
+type FreeCell struct {
+	rules
+}
+
+func (FreeCell) Name(opts Options) string {
+	return "FreeCell"
+}
+
+func (FreeCell) Layout(opts Options) Layout {
+	return Layout{Cells: NumFreeCells, Foundations: NumFoundations, Tableau: NumFreeCellTableau}
+}
+
+// Slot puts the free cells at the top left and the foundations at the top
+// right, above the columns.
+func (FreeCell) Slot(opts Options, id PileID) Slot {
+	switch id.Kind {
+	case PileCell:
+		return Slot{X: float64(id.Index), Y: 0}
+	case PileFoundation:
+		return Slot{X: float64(NumFreeCells + id.Index), Y: 0}
+	default:
+		return Slot{X: float64(id.Index), Y: 1, Spread: true}
+	}
+}
+
+func (FreeCell) Deck(opts Options) []Card {
+	return NewDeck()
+}
+
+// Deal lays the cards out face up a row at a time, from the start of the
+// deck, as Microsoft FreeCell does.
+func (FreeCell) Deal(state *State, deck []Card) {
+	for i, card := range deck {
+		card.FaceUp = true
+		state.Tableau[i%len(state.Tableau)].push(card)
+	}
+}
+
+func (FreeCell) Stock(opts Options) StockRules {
+	return StockRules{}
+}
+
+func (FreeCell) NumberedDeck(number uint64) []Card {
+	return MicrosoftDeck(number)
+}
+
+func (FreeCell) RandomDealNumber() uint64 {
+	return 1 + RandomSeed()%NumMicrosoftDeals
+}
+
+func (FreeCell) NumDeals() uint64 {
+	return NumMicrosoftDeals
+}
+
+func (FreeCell) CanPickUp(s *State, id PileID, index int) bool {
+	switch id.Kind {
+	case PileTableau:
+		// Columns are dealt in any order, so only a run can be lifted
+		return isAlternatingRun(s.Pile(id).Cards[index:])
+	case PileFoundation, PileCell:
+		return isTopCard(s, id, index)
+	default:
+		return false
+	}
+}
+
+func (FreeCell) CanAccept(s *State, id PileID, cards []Card) bool {
+	switch id.Kind {
+	case PileTableau:
+		return buildsOnAlternating(s.Pile(id), cards, anyCard)
+	case PileFoundation:
+		return buildsOnFoundation(s.Pile(id), cards)
+	case PileCell:
+		// A free cell holds a single card
+		return len(cards) == 1 && s.Pile(id).IsEmpty()
+	default:
+		return false
+	}
+}
+
+// MaxMoveCount allows moving a run only as far as the free cells and empty
+// columns allow it to be shuffled across one card at a time (a supermove).
+// An empty destination column cannot help with its own move.
+func (FreeCell) MaxMoveCount(s *State, to PileID) int {
+	freeCells := 0
+	for _, cell := range s.Cells {
+		if cell.IsEmpty() {
+			freeCells++
+		}
+	}
+	emptyColumns := 0
+	for i, column := range s.Tableau {
+		if column.IsEmpty() && TableauID(i) != to {
+			emptyColumns++
+		}
+	}
+	return (freeCells + 1) << emptyColumns
+}
+
+func (FreeCell) CanAutoFinish(s *State) bool {
+	return canFinishByFoundations(s)
+}
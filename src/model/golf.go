@@ -0,0 +1,91 @@
+package model
+
+const NumGolfTableau = 7
+const NumGolfRows = 5
+
+// Golf clears seven face up columns by playing their top cards onto the
+// waste, one rank above or below the card showing there. The stock is only
+// gone through once.
+type Golf struct {
+	rules
+}
+
+func (Golf) Name(opts Options) string {
+	return "Golf"
+}
+
+func (Golf) Layout(opts Options) Layout {
+	return Layout{Stock: true, Waste: true, Tableau: NumGolfTableau}
+}
+
+// Slot puts the draw and overturned piles underneath the columns.
+func (Golf) Slot(opts Options, id PileID) Slot {
+	switch id.Kind {
+	case PileStock:
+		return Slot{X: 0, Y: 2}
+	case PileWaste:
+		return Slot{X: 1, Y: 2}
+	default:
+		return Slot{X: float64(id.Index), Y: 0, Spread: true}
+	}
+}
+
+func (Golf) Deck(opts Options) []Card {
+	return NewDeck()
+}
+
+// Deal lays out the columns face up and starts the waste with a card from
+// the stock.
+func (Golf) Deal(state *State, deck []Card) {
+	state.Stock.push(deck...)
+	for i := range state.Tableau {
+		dealColumn(state, i, NumGolfRows, NumGolfRows)
+	}
+	turnOverWaste(state)
+}
+
+func (Golf) Stock(opts Options) StockRules {
+	return StockRules{DrawCount: 1}
+}
+
+func (Golf) CanPickUp(s *State, id PileID, index int) bool {
+	return id.Kind == PileTableau && isTopCard(s, id, index)
+}
+
+// CanAccept takes single cards onto the waste. Nothing goes onto a king.
+func (Golf) CanAccept(s *State, id PileID, cards []Card) bool {
+	top, ok := s.Pile(id).Top()
+	return id.Kind == PileWaste && len(cards) == 1 && ok && top.Number != King && isNeighbour(cards[0], top, false)
+}
+
+// IsWon reports whether the columns have been cleared. Cards may be left in
+// the stock.
+func (Golf) IsWon(s *State) bool {
+	return tableauCleared(s)
+}
+
+// turnOverWaste starts the waste with the top card of the stock.
+func turnOverWaste(state *State) {
+	card := state.Stock.pop(1)
+	setFaceUp(card, true)
+	state.Waste.push(card...)
+}
+
+// isNeighbour reports whether two cards are one rank apart, counting the
+// king and the ace as neighbours if wrap is set.
+func isNeighbour(a, b Card, wrap bool) bool {
+	if a.Number.IsOneLessThan(b.Number) || a.Number.IsOneMoreThan(b.Number) {
+		return true
+	}
+	return wrap && (a.Number == Ace && b.Number == King || a.Number == King && b.Number == Ace)
+}
+
+// tableauCleared reports whether every tableau pile is empty.
+func tableauCleared(s *State) bool {
+	for _, pile := range s.Tableau {
+		if !pile.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
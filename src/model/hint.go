@@ -0,0 +1,127 @@
+package model
+
+import "sort"
+
+// LegalMoves lists every move that can be made from the current state.
+func (s *State) LegalMoves() []Move {
+	moves := []Move{}
+	if s.Validate(DrawMove()) == nil {
+		moves = append(moves, DrawMove())
+	} else if s.Validate(RecycleMove()) == nil {
+		moves = append(moves, RecycleMove())
+	}
+
+	destinations := []PileID{}
+	for _, id := range s.PileIDs() {
+		if id.Kind != PileStock {
+			destinations = append(destinations, id)
+		}
+	}
+
+	for _, from := range s.PileIDs() {
+		pile := s.Pile(from)
+		for index := range pile.Cards {
+			if !s.CanPickUp(from, index) {
+				continue
+			}
+			cards := pile.Cards[index:]
+			for _, to := range destinations {
+				if to != from && s.CanAccept(to, cards) && len(cards) <= s.MaxMoveCount(to) {
+					moves = append(moves, CardsMove(from, to, len(cards)))
+				}
+			}
+		}
+	}
+	return moves
+}
+
+// Hints returns the legal moves worth suggesting to a player, best first.
+// Moves that only shuffle cards around without making progress are left out.
+func (s *State) Hints() []Move {
+	type rankedMove struct {
+		move Move
+		rank int
+	}
+	ranked := []rankedMove{}
+	for _, m := range s.LegalMoves() {
+		if rank := s.RankMove(m); rank > 0 {
+			ranked = append(ranked, rankedMove{move: m, rank: rank})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].rank > ranked[j].rank
+	})
+
+	hints := make([]Move, len(ranked))
+	for i, r := range ranked {
+		hints[i] = r.move
+	}
+	return hints
+}
+
+// joinsSuit reports whether a move puts cards onto a card of the same suit.
+func (s *State) joinsSuit(m Move) bool {
+	from := s.Pile(m.From)
+	top, ok := s.Pile(m.To).Top()
+	return ok && m.To.Kind == PileTableau && top.Suit == from.Cards[from.Len()-m.Count].Suit
+}
+
+// RankMove scores how useful a legal move is likely to be. Moves ranked zero
+// or below are not worth suggesting to a player, but may still be needed.
+func (s *State) RankMove(m Move) int {
+	switch m.Kind {
+	case MoveDraw:
+		return 2
+	case MoveRecycle:
+		return 1
+	}
+
+	from := s.Pile(m.From)
+	index := from.Len() - m.Count
+	card := from.Cards[index]
+	var below *Card
+	if index > 0 {
+		below = &from.Cards[index-1]
+	}
+
+	switch {
+	case m.To.Kind == PileFoundation && m.From.Kind == PileFoundation:
+		return 0
+	case m.To.Kind == PileFoundation:
+		// Low cards are always safe to play home
+		return 100 - int(card.Number)
+	case m.To.Kind == PileWaste:
+		// Playing onto the waste is how cards leave the table in Golf and TriPeaks
+		return 70
+	case m.From.Kind == PileTableau && below != nil && !below.FaceUp:
+		// Turning over a card is the main way to make progress, more so in tall columns
+		return 80 + index
+	case m.From.Kind == PileWaste:
+		return 60
+	case m.From.Kind == PileCell:
+		// Shuffling cards between free cells gets nowhere
+		if m.To.Kind == PileCell {
+			return 0
+		}
+		return 60
+	case m.From.Kind == PileTableau && below == nil:
+		// Emptying a column is only useful if it isn't a card moving to another empty column
+		if s.Pile(m.To).IsEmpty() && m.To.Kind == PileTableau {
+			return 0
+		}
+		return 50
+	case m.To.Kind == PileCell:
+		// Parking a card in a free cell uses up space, so it is a last resort
+		return 3
+	case m.From.Kind == PileTableau && s.joinsSuit(m) &&
+		!(below.Suit == card.Suit && card.Number.IsOneLessThan(below.Number)):
+		// Building a run of a single suit is how Spider is won, unless the
+		// cards were already part of one
+		return 40
+	case m.From.Kind == PileFoundation:
+		return 5
+	default:
+		// Moving part of a face-up run between columns rarely helps
+		return 0
+	}
+}
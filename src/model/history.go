@@ -0,0 +1,112 @@
+package model
+
+import "fmt"
+
+// History records applied steps so that they can be undone and redone. The
+// history is unlimited; applying a new move discards anything that was undone.
+type History struct {
+	done   []Step
+	undone []Step
+}
+
+// NewHistory recreates a history from its applied and undone steps, e.g.
+// when loading a saved game.
+func NewHistory(done, undone []Step) History {
+	return History{done: done, undone: undone}
+}
+
+// ReplayHistory rebuilds the history that led to a state by playing its
+// moves again from the deal, e.g. when loading a saved game, so that undoing
+// can't go wrong on steps that don't match the state. It fails if the moves
+// don't lead to the state. Undone steps are dropped if they can't all be
+// redone.
+func ReplayHistory(s *State, done, undone []Step) (History, error) {
+	if err := s.Options.Game.CheckSeed(s.Seed); err != nil {
+		return History{}, err
+	}
+	replay := NewGame(s.Seed, s.Options)
+	steps := make([]Step, 0, len(done))
+	for i, step := range done {
+		replayed, err := replay.Apply(step.Move)
+		if err != nil {
+			return History{}, fmt.Errorf("move %d (%s): %w", i+1, step.Move, err)
+		}
+		steps = append(steps, replayed)
+	}
+	if !replay.Equal(s) {
+		return History{}, fmt.Errorf("the moves made don't lead to the position")
+	}
+
+	// The undone steps are redone last first
+	for i := len(undone) - 1; i >= 0; i-- {
+		if _, err := replay.Apply(undone[i].Move); err != nil {
+			undone = nil
+			break
+		}
+	}
+	return NewHistory(steps, undone), nil
+}
+
+// Apply performs a move on the state and records it.
+func (h *History) Apply(s *State, m Move) (Step, error) {
+	step, err := s.Apply(m)
+	if err != nil {
+		return Step{}, err
+	}
+	h.done = append(h.done, step)
+	h.undone = nil
+	return step, nil
+}
+
+// PeekUndo returns the step that the next call to Undo would revert.
+func (h *History) PeekUndo() (Step, bool) {
+	if len(h.done) == 0 {
+		return Step{}, false
+	}
+	return h.done[len(h.done)-1], true
+}
+
+// PeekRedo returns the step that the next call to Redo would re-apply.
+func (h *History) PeekRedo() (Step, bool) {
+	if len(h.undone) == 0 {
+		return Step{}, false
+	}
+	return h.undone[len(h.undone)-1], true
+}
+
+func (h *History) Undo(s *State) (Step, bool) {
+	step, ok := h.PeekUndo()
+	if !ok {
+		return Step{}, false
+	}
+	s.Revert(step)
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, step)
+	return step, true
+}
+
+func (h *History) Redo(s *State) (Step, bool) {
+	next, ok := h.PeekRedo()
+	if !ok {
+		return Step{}, false
+	}
+	step, err := s.Apply(next.Move)
+	if err != nil {
+		// The state no longer matches the history, so the redo list is useless
+		h.undone = nil
+		return Step{}, false
+	}
+	h.done = append(h.done, step)
+	h.undone = h.undone[:len(h.undone)-1]
+	return step, true
+}
+
+// Steps returns the applied steps, oldest first.
+func (h *History) Steps() []Step {
+	return h.done
+}
+
+// Undone returns the steps that can be redone, in the order they were undone.
+func (h *History) Undone() []Step {
+	return h.undone
+}
@@ -0,0 +1,79 @@
+package model
+
+import "fmt"
+
+const NumFoundations = 4
+const NumTableau = 7
+
+// Klondike is the classic game: seven columns of growing height, built down
+// in alternating colors, with a stock that can be gone through again.
+type Klondike struct {
+	rules
+}
+
+func (Klondike) Name(opts Options) string {
+	return fmt.Sprintf("Klondike, draw %d", opts.DrawCount) + passesSuffix(opts)
+}
+
+func (Klondike) Layout(opts Options) Layout {
+	return Layout{Stock: true, Waste: true, Foundations: NumFoundations, Tableau: NumTableau}
+}
+
+// Slot puts the draw and overturned piles at the top left and the
+// foundations at the top right, above the columns.
+func (Klondike) Slot(opts Options, id PileID) Slot {
+	switch id.Kind {
+	case PileStock:
+		return Slot{X: 0, Y: 0}
+	case PileWaste:
+		return Slot{X: 1, Y: 0}
+	case PileFoundation:
+		return Slot{X: float64(3 + id.Index), Y: 0}
+	default:
+		return Slot{X: float64(id.Index), Y: 1, Spread: true}
+	}
+}
+
+func (Klondike) Deck(opts Options) []Card {
+	return NewDeck()
+}
+
+// Deal gives column i i+1 cards with the last of them face up. Whatever is
+// left over becomes the stock.
+func (Klondike) Deal(state *State, deck []Card) {
+	state.Stock.push(deck...)
+	for i := range state.Tableau {
+		dealColumn(state, i, i+1, 1)
+	}
+}
+
+func (Klondike) Stock(opts Options) StockRules {
+	return StockRules{DrawCount: max(opts.DrawCount, 1), Recycle: true, Passes: opts.Passes}
+}
+
+func (Klondike) CanPickUp(s *State, id PileID, index int) bool {
+	switch id.Kind {
+	case PileTableau:
+		return s.Pile(id).Cards[index].FaceUp && isAlternatingRun(s.Pile(id).Cards[index:])
+	case PileWaste, PileFoundation:
+		// Only the top card can be taken, even when the waste is fanned out
+		return isTopCard(s, id, index)
+	default:
+		return false
+	}
+}
+
+func (Klondike) CanAccept(s *State, id PileID, cards []Card) bool {
+	switch id.Kind {
+	case PileTableau:
+		return buildsOnAlternating(s.Pile(id), cards, isKing)
+	case PileFoundation:
+		return buildsOnFoundation(s.Pile(id), cards)
+	default:
+		return false
+	}
+}
+
+func (Klondike) CanAutoFinish(s *State) bool {
+	return canFinishByFoundations(s)
+}
@@ -0,0 +1,31 @@
+package model
+
+// NumMicrosoftDeals is the number of deals the original Microsoft FreeCell
+// offered.
+const NumMicrosoftDeals = 32000
+
+// MicrosoftDeck returns the deck of a Microsoft FreeCell deal number, face up
+// and in the order the cards are dealt, so that the classic deals can be
+// played by their numbers. Only numbers from 1 to NumMicrosoftDeals are
+// classic deals, see Game.CheckSeed.
+func MicrosoftDeck(number uint64) []Card {
+	// The original game sorts the deck by rank, then clubs, diamonds, hearts and spades
+	deck := make([]Card, 0, len(Suits)*len(Numbers))
+	for _, n := range Numbers {
+		for _, suit := range []Suit{Club, Diamond, Heart, Spade} {
+			deck = append(deck, Card{Number: n, Suit: suit, FaceUp: true})
+		}
+	}
+
+	// Pick each card with the Microsoft C runtime's rand(), filling the gap
+	// it leaves with the last card of the deck
+	seed := uint32(number)
+	dealt := make([]Card, 0, len(deck))
+	for left := len(deck); left > 0; left-- {
+		seed = seed*214013 + 2531011
+		i := int(seed>>16&0x7fff) % left
+		dealt = append(dealt, deck[i])
+		deck[i] = deck[left-1]
+	}
+	return dealt
+}
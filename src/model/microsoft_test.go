@@ -0,0 +1,44 @@
+package model
+
+import "testing"
+
+func TestMicrosoftDeckFirstDeal(t *testing.T) {
+	// The first row of the classic deal #1, left to right
+	want := []Card{
+		{Number: Jack, Suit: Diamond},
+		{Number: Two, Suit: Diamond},
+		{Number: Nine, Suit: Heart},
+		{Number: Jack, Suit: Club},
+		{Number: Five, Suit: Diamond},
+		{Number: Seven, Suit: Heart},
+		{Number: Seven, Suit: Club},
+		{Number: Five, Suit: Heart},
+	}
+	deck := MicrosoftDeck(1)
+	for i, card := range want {
+		if deck[i].Number != card.Number || deck[i].Suit != card.Suit {
+			t.Errorf("card %d of deal #1 is %s, want %s", i+1, deck[i], card)
+		}
+	}
+}
+
+func TestCheckSeed(t *testing.T) {
+	tests := []struct {
+		game Game
+		seed uint64
+		ok   bool
+	}{
+		{GameFreeCell, 1, true},
+		{GameFreeCell, NumMicrosoftDeals, true},
+		{GameFreeCell, 0, false},
+		{GameFreeCell, NumMicrosoftDeals + 1, false},
+		{GameFreeCell, 1<<32 + 1, false},
+		{GameKlondike, 0, true},
+		{GameKlondike, 1<<32 + 1, true},
+	}
+	for _, tt := range tests {
+		if err := tt.game.CheckSeed(tt.seed); (err == nil) != tt.ok {
+			t.Errorf("%s seed %d: got error %v, want ok %v", tt.game, tt.seed, err, tt.ok)
+		}
+	}
+}
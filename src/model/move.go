@@ -0,0 +1,306 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrIllegalMove = errors.New("illegal move")
+
+type MoveKind int
+
+const (
+	// MoveDraw turns cards from the stock onto the waste, or deals a card
+	// onto every tableau column, as the variant's StockRules say.
+	MoveDraw MoveKind = iota
+	// MoveRecycle turns the whole waste back over onto the stock.
+	MoveRecycle
+	// MoveCards moves the top Count cards of From onto To.
+	MoveCards
+)
+
+type Move struct {
+	Kind  MoveKind
+	From  PileID
+	To    PileID
+	Count int
+}
+
+func DrawMove() Move {
+	return Move{Kind: MoveDraw, From: StockID, To: WasteID}
+}
+
+func RecycleMove() Move {
+	return Move{Kind: MoveRecycle, From: WasteID, To: StockID}
+}
+
+func CardsMove(from, to PileID, count int) Move {
+	return Move{Kind: MoveCards, From: from, To: to, Count: count}
+}
+
+func (m Move) String() string {
+	switch m.Kind {
+	case MoveDraw:
+		return "draw"
+	case MoveRecycle:
+		return "recycle"
+	default:
+		return fmt.Sprintf("%d from %s to %s", m.Count, m.From, m.To)
+	}
+}
+
+func illegal(reason string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrIllegalMove, fmt.Sprintf(reason, args...))
+}
+
+// CanPickUp reports whether the cards of a pile from index upwards may be
+// lifted as a unit.
+func (s *State) CanPickUp(id PileID, index int) bool {
+	pile := s.Pile(id)
+	if pile == nil || index < 0 || index >= pile.Len() {
+		return false
+	}
+	return s.Options.Variant().CanPickUp(s, id, index)
+}
+
+// CanAccept reports whether the given cards may be placed onto a pile.
+func (s *State) CanAccept(id PileID, cards []Card) bool {
+	if s.Pile(id) == nil || len(cards) == 0 {
+		return false
+	}
+	return s.Options.Variant().CanAccept(s, id, cards)
+}
+
+// MaxMoveCount returns how many cards can be moved onto a pile at once.
+func (s *State) MaxMoveCount(to PileID) int {
+	return s.Options.Variant().MaxMoveCount(s, to)
+}
+
+// StockRules describe what drawing from the stock does in this game.
+func (s *State) StockRules() StockRules {
+	return s.Options.Variant().Stock(s.Options)
+}
+
+// OutOfPasses reports whether the stock can no longer be recycled, either
+// because the game never allows it or because the pass limit has been used up.
+func (s *State) OutOfPasses() bool {
+	stock := s.StockRules()
+	return !stock.Recycle || (stock.Passes > 0 && s.Recycles >= stock.Passes-1)
+}
+
+func (s *State) Validate(m Move) error {
+	switch m.Kind {
+	case MoveDraw:
+		if s.Stock.IsEmpty() {
+			return illegal("stock is empty")
+		}
+		if s.StockRules().DrawCount == 0 {
+			for _, column := range s.Tableau {
+				if column.IsEmpty() {
+					return illegal("cannot deal while a column is empty")
+				}
+			}
+			if s.Stock.Len() < len(s.Tableau) {
+				return illegal("not enough cards in the stock to deal a row")
+			}
+		}
+		return nil
+	case MoveRecycle:
+		if !s.StockRules().Recycle {
+			return illegal("the stock can only be gone through once")
+		}
+		if s.OutOfPasses() {
+			return illegal("no passes through the stock are left")
+		}
+		if !s.Stock.IsEmpty() {
+			return illegal("stock is not empty")
+		}
+		if s.Waste.IsEmpty() {
+			return illegal("waste is empty")
+		}
+		return nil
+	case MoveCards:
+		if m.From == m.To {
+			return illegal("source and destination are the same pile")
+		}
+		from := s.Pile(m.From)
+		if from == nil || s.Pile(m.To) == nil {
+			return illegal("unknown pile")
+		}
+		if m.Count < 1 || m.Count > from.Len() {
+			return illegal("cannot move %d cards from %s", m.Count, m.From)
+		}
+		if !s.CanPickUp(m.From, from.Len()-m.Count) {
+			return illegal("cannot pick up %d cards from %s", m.Count, m.From)
+		}
+		if !s.CanAccept(m.To, from.Cards[from.Len()-m.Count:]) {
+			return illegal("%s does not accept %s", m.To, from.Cards[from.Len()-m.Count])
+		}
+		if limit := s.MaxMoveCount(m.To); m.Count > limit {
+			return illegal("only %d cards can be moved to %s at once", limit, m.To)
+		}
+		return nil
+	default:
+		return illegal("unknown move kind %d", m.Kind)
+	}
+}
+
+// Step is a move that has been applied to a state, along with the side
+// effects needed to take it back again.
+type Step struct {
+	Move Move
+	// Flipped is set when the move exposed a face-down tableau card and
+	// turned it face up.
+	Flipped bool
+	// Drawn is the number of cards a draw turned over, which can be fewer
+	// than the draw count near the bottom of the stock.
+	Drawn int
+	// Revealed lists the other piles whose top card the move uncovered and
+	// turned face up.
+	Revealed []PileID
+	// Completed lists the cards the variant cleared to the foundations as
+	// part of the move, such as a finished suit in Spider.
+	Completed []Completion
+}
+
+// Completion is a group of cards cleared from the top of a pile to a
+// foundation.
+type Completion struct {
+	From       PileID
+	Count      int
+	Foundation int
+	// Flipped is set when clearing the cards exposed a face-down card and
+	// turned it face up.
+	Flipped bool
+}
+
+// Apply validates and performs a move. A tableau card left exposed by the
+// move is turned face up, and any cards the variant clears once the move
+// is made are sent to a foundation.
+func (s *State) Apply(m Move) (Step, error) {
+	if err := s.Validate(m); err != nil {
+		return Step{}, err
+	}
+
+	step := Step{Move: m}
+	stock := s.StockRules()
+	switch {
+	case m.Kind == MoveDraw && stock.DrawCount == 0:
+		step.Drawn = len(s.Tableau)
+		for i := range s.Tableau {
+			card := s.Stock.pop(1)
+			setFaceUp(card, true)
+			s.Tableau[i].push(card...)
+			step.Completed = s.clearCompleted(TableauID(i), step.Completed)
+		}
+	case m.Kind == MoveDraw:
+		// Turning a group of cards over reverses their order
+		step.Drawn = min(stock.DrawCount, s.Stock.Len())
+		cards := s.Stock.pop(step.Drawn)
+		reverseCards(cards)
+		setFaceUp(cards, true)
+		s.Waste.push(cards...)
+	case m.Kind == MoveRecycle:
+		cards := s.Waste.pop(s.Waste.Len())
+		reverseCards(cards)
+		setFaceUp(cards, false)
+		s.Stock.push(cards...)
+		s.Recycles++
+	case m.Kind == MoveCards:
+		from := s.Pile(m.From)
+		s.Pile(m.To).push(from.pop(m.Count)...)
+		if m.From.Kind == PileTableau && !from.IsEmpty() && !from.Cards[from.Len()-1].FaceUp {
+			from.Cards[from.Len()-1].FaceUp = true
+			step.Flipped = true
+		}
+		for _, id := range s.Options.Variant().Uncovered(s, m.From) {
+			pile := s.Pile(id)
+			if !pile.IsEmpty() && !pile.Cards[pile.Len()-1].FaceUp {
+				pile.Cards[pile.Len()-1].FaceUp = true
+				step.Revealed = append(step.Revealed, id)
+			}
+		}
+		step.Completed = s.clearCompleted(m.To, step.Completed)
+	}
+	return step, nil
+}
+
+// clearCompleted moves any cards the variant clears from the top of a pile to
+// the first empty foundation, or the last one if they are all in use, adding
+// them to the completions so far.
+func (s *State) clearCompleted(id PileID, completed []Completion) []Completion {
+	count := s.Options.Variant().Cleared(s, id)
+	if count == 0 {
+		return completed
+	}
+	foundation := 0
+	for foundation < len(s.Foundations)-1 && !s.Foundations[foundation].IsEmpty() {
+		foundation++
+	}
+
+	pile := s.Pile(id)
+	s.Foundations[foundation].push(pile.pop(count)...)
+	completion := Completion{From: id, Count: count, Foundation: foundation}
+	if id.Kind == PileTableau && !pile.IsEmpty() && !pile.Cards[pile.Len()-1].FaceUp {
+		pile.Cards[pile.Len()-1].FaceUp = true
+		completion.Flipped = true
+	}
+	return append(completed, completion)
+}
+
+// Revert takes back a step previously returned by Apply. Steps must be
+// reverted in the reverse order they were applied.
+func (s *State) Revert(step Step) {
+	// Put back any cards the move cleared before taking back the move itself
+	for i := len(step.Completed) - 1; i >= 0; i-- {
+		completion := step.Completed[i]
+		pile := s.Pile(completion.From)
+		if completion.Flipped {
+			pile.Cards[pile.Len()-1].FaceUp = false
+		}
+		pile.push(s.Foundations[completion.Foundation].pop(completion.Count)...)
+	}
+	for _, id := range step.Revealed {
+		pile := s.Pile(id)
+		pile.Cards[pile.Len()-1].FaceUp = false
+	}
+
+	m := step.Move
+	switch {
+	case m.Kind == MoveDraw && s.StockRules().DrawCount == 0:
+		for i := len(s.Tableau) - 1; i >= 0; i-- {
+			card := s.Tableau[i].pop(1)
+			setFaceUp(card, false)
+			s.Stock.push(card...)
+		}
+	case m.Kind == MoveDraw:
+		cards := s.Waste.pop(step.Drawn)
+		reverseCards(cards)
+		setFaceUp(cards, false)
+		s.Stock.push(cards...)
+	case m.Kind == MoveRecycle:
+		cards := s.Stock.pop(s.Stock.Len())
+		reverseCards(cards)
+		setFaceUp(cards, true)
+		s.Waste.push(cards...)
+		s.Recycles--
+	case m.Kind == MoveCards:
+		from := s.Pile(m.From)
+		if step.Flipped {
+			from.Cards[from.Len()-1].FaceUp = false
+		}
+		from.push(s.Pile(m.To).pop(m.Count)...)
+	}
+}
+
+func reverseCards(cards []Card) {
+	for i, j := 0, len(cards)-1; i < j; i, j = i+1, j-1 {
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+func setFaceUp(cards []Card, faceUp bool) {
+	for i := range cards {
+		cards[i].FaceUp = faceUp
+	}
+}
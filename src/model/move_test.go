@@ -0,0 +1,316 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func up(n Number, suit Suit) Card {
+	return Card{Number: n, Suit: suit, FaceUp: true}
+}
+
+func down(n Number, suit Suit) Card {
+	return Card{Number: n, Suit: suit}
+}
+
+// fingerprint describes every pile of a state, card by card, so that states
+// can be compared after undoing and redoing moves.
+func fingerprint(s *State) string {
+	var b strings.Builder
+	for _, id := range s.PileIDs() {
+		fmt.Fprintf(&b, "%s:", id)
+		for _, card := range s.Pile(id).Cards {
+			fmt.Fprintf(&b, " %s/%d/%t", card, card.Deck, card.FaceUp)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "recycles: %d", s.Recycles)
+	return b.String()
+}
+
+func TestValidateCardsMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		tableau [][]Card
+		found   [][]Card
+		move    Move
+		legal   bool
+	}{
+		{
+			name:    "red on black one higher",
+			tableau: [][]Card{{up(Seven, Spade)}, {up(Six, Heart)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 1),
+			legal:   true,
+		},
+		{
+			name:    "same color",
+			tableau: [][]Card{{up(Seven, Spade)}, {up(Six, Club)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 1),
+		},
+		{
+			name:    "wrong rank",
+			tableau: [][]Card{{up(Seven, Spade)}, {up(Five, Heart)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 1),
+		},
+		{
+			name:    "king to an empty column",
+			tableau: [][]Card{{}, {up(King, Heart)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 1),
+			legal:   true,
+		},
+		{
+			name:    "queen to an empty column",
+			tableau: [][]Card{{}, {up(Queen, Heart)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 1),
+		},
+		{
+			name:    "run of cards",
+			tableau: [][]Card{{up(Nine, Club)}, {down(Two, Club), up(Eight, Heart), up(Seven, Spade)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 2),
+			legal:   true,
+		},
+		{
+			name:    "face-down card",
+			tableau: [][]Card{{up(Nine, Club)}, {down(Eight, Heart), up(Seven, Spade)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 2),
+		},
+		{
+			name:    "more cards than the pile holds",
+			tableau: [][]Card{{up(Seven, Spade)}, {up(Six, Heart)}},
+			move:    CardsMove(TableauID(1), TableauID(0), 2),
+		},
+		{
+			name:    "onto itself",
+			tableau: [][]Card{{up(Seven, Spade)}},
+			move:    CardsMove(TableauID(0), TableauID(0), 1),
+		},
+		{
+			name:    "ace to an empty foundation",
+			tableau: [][]Card{{up(Ace, Heart)}},
+			move:    CardsMove(TableauID(0), FoundationID(0), 1),
+			legal:   true,
+		},
+		{
+			name:    "two onto its ace",
+			tableau: [][]Card{{up(Two, Heart)}},
+			found:   [][]Card{{up(Ace, Heart)}},
+			move:    CardsMove(TableauID(0), FoundationID(0), 1),
+			legal:   true,
+		},
+		{
+			name:    "two onto another suit's ace",
+			tableau: [][]Card{{up(Two, Spade)}},
+			found:   [][]Card{{up(Ace, Heart)}},
+			move:    CardsMove(TableauID(0), FoundationID(0), 1),
+		},
+		{
+			name:    "three onto an ace",
+			tableau: [][]Card{{up(Three, Heart)}},
+			found:   [][]Card{{up(Ace, Heart)}},
+			move:    CardsMove(TableauID(0), FoundationID(0), 1),
+		},
+		{
+			name:    "two cards to a foundation",
+			tableau: [][]Card{{up(Two, Spade), up(Ace, Heart)}},
+			move:    CardsMove(TableauID(0), FoundationID(0), 2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState(DefaultOptions)
+			for i, cards := range tt.tableau {
+				s.Tableau[i].push(cards...)
+			}
+			for i, cards := range tt.found {
+				s.Foundations[i].push(cards...)
+			}
+			if err := s.Validate(tt.move); (err == nil) != tt.legal {
+				t.Errorf("Validate(%s) = %v, want legal %v", tt.move, err, tt.legal)
+			}
+		})
+	}
+}
+
+func TestApplyFlipsExposedCard(t *testing.T) {
+	s := NewState(DefaultOptions)
+	s.Tableau[0].push(up(Seven, Spade))
+	s.Tableau[1].push(down(Five, Club), up(Six, Heart))
+	before := fingerprint(s)
+
+	step, err := s.Apply(CardsMove(TableauID(1), TableauID(0), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !step.Flipped || !s.Tableau[1].Cards[0].FaceUp {
+		t.Errorf("exposed card was not turned face up")
+	}
+	if s.Tableau[0].Len() != 2 {
+		t.Errorf("destination holds %d cards, want 2", s.Tableau[0].Len())
+	}
+
+	s.Revert(step)
+	if after := fingerprint(s); after != before {
+		t.Errorf("Revert gave\n%s\nwant\n%s", after, before)
+	}
+}
+
+func TestDrawAndRecycle(t *testing.T) {
+	opts := DefaultOptions
+	opts.DrawCount = 3
+	s := NewState(opts)
+	s.Stock.push(down(Ace, Club), down(Two, Club), down(Three, Club), down(Four, Club))
+	start := fingerprint(s)
+
+	if err := s.Validate(RecycleMove()); err == nil {
+		t.Errorf("recycled while the stock still holds cards")
+	}
+	var steps []Step
+	for _, drawn := range []int{3, 1} {
+		step, err := s.Apply(DrawMove())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step.Drawn != drawn {
+			t.Errorf("drew %d cards, want %d", step.Drawn, drawn)
+		}
+		steps = append(steps, step)
+	}
+	if top, _ := s.Waste.Top(); top.Number != Ace || !top.FaceUp {
+		t.Errorf("top of the waste is %s, want the ace face up", top)
+	}
+	if err := s.Validate(DrawMove()); err == nil {
+		t.Errorf("drew from an empty stock")
+	}
+
+	step, err := s.Apply(RecycleMove())
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps = append(steps, step)
+	if s.Recycles != 1 || !s.Waste.IsEmpty() {
+		t.Errorf("recycle left %d cards in the waste after %d recycles", s.Waste.Len(), s.Recycles)
+	}
+	for i, card := range s.Stock.Cards {
+		if card.Number != Number(i+1) || card.FaceUp {
+			t.Errorf("stock card %d is %s face up %t, want %s face down", i, card, card.FaceUp, Number(i+1))
+		}
+	}
+
+	// Taking every step back leaves the state as it started, recycle count included
+	for i := len(steps) - 1; i >= 0; i-- {
+		s.Revert(steps[i])
+	}
+	if s.Recycles != 0 || fingerprint(s) != start {
+		t.Errorf("reverting gave\n%s\nwant\n%s", fingerprint(s), start)
+	}
+}
+
+func TestRecyclePassLimit(t *testing.T) {
+	opts := DefaultOptions
+	opts.Passes = 2
+	s := NewState(opts)
+	s.Waste.push(up(Ace, Club))
+	if _, err := s.Apply(RecycleMove()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Apply(DrawMove()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(RecycleMove()); err == nil {
+		t.Errorf("recycled beyond the pass limit")
+	}
+}
+
+// TestUndoRedoRoundTrip plays random legal moves in every game, then checks
+// that undoing them all restores the deal and redoing them all restores the
+// final position.
+func TestUndoRedoRoundTrip(t *testing.T) {
+	for _, opts := range []Options{
+		{Game: GameKlondike, DrawCount: 1, Suits: 1},
+		{Game: GameKlondike, DrawCount: 3, Suits: 1},
+		{Game: GameFreeCell, DrawCount: 1, Suits: 1},
+		{Game: GameSpider, DrawCount: 1, Suits: 2},
+		{Game: GameYukon, DrawCount: 1, Suits: 1},
+		{Game: GameGolf, DrawCount: 1, Suits: 1},
+		{Game: GamePyramid, DrawCount: 1, Suits: 1},
+		{Game: GameTriPeaks, DrawCount: 1, Suits: 1},
+	} {
+		t.Run(opts.Name(), func(t *testing.T) {
+			for seed := uint64(1); seed <= 5; seed++ {
+				s := NewGame(seed, opts)
+				rng := NewRand(seed)
+				history := History{}
+				start := fingerprint(s)
+				for i := 0; i < 200; i++ {
+					moves := s.LegalMoves()
+					if len(moves) == 0 {
+						break
+					}
+					if _, err := history.Apply(s, moves[rng.Intn(len(moves))]); err != nil {
+						t.Fatalf("seed %d: legal move failed: %v", seed, err)
+					}
+				}
+				end := fingerprint(s)
+				if err := s.CheckCards(); err != nil {
+					t.Fatalf("seed %d: %v", seed, err)
+				}
+
+				for {
+					if _, ok := history.Undo(s); !ok {
+						break
+					}
+				}
+				if got := fingerprint(s); got != start {
+					t.Fatalf("seed %d: undoing every move gave\n%s\nwant\n%s", seed, got, start)
+				}
+				for {
+					if _, ok := history.Redo(s); !ok {
+						break
+					}
+				}
+				if got := fingerprint(s); got != end {
+					t.Fatalf("seed %d: redoing every move gave\n%s\nwant\n%s", seed, got, end)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayHistory(t *testing.T) {
+	s := NewGame(1, DefaultOptions)
+	history := History{}
+	for _, move := range []Move{DrawMove(), DrawMove(), DrawMove()} {
+		if _, err := history.Apply(s, move); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history.Undo(s)
+	done, undone := history.Steps(), history.Undone()
+
+	replayed, err := ReplayHistory(s, done, undone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.Steps()) != 2 || len(replayed.Undone()) != 1 {
+		t.Errorf("replayed %d steps with %d undone, want 2 and 1", len(replayed.Steps()), len(replayed.Undone()))
+	}
+
+	// Steps that don't lead to the state, or can't be played at all, are refused
+	if _, err := ReplayHistory(s, done[:1], nil); err == nil {
+		t.Errorf("replayed a history that stops short of the state")
+	}
+	bad := append([]Step{{Move: CardsMove(TableauID(9), TableauID(0), 5)}}, done...)
+	if _, err := ReplayHistory(s, bad, nil); err == nil {
+		t.Errorf("replayed a history with an impossible move")
+	}
+
+	// Undone steps that can't be redone are dropped
+	replayed, err = ReplayHistory(s, done, []Step{{Move: RecycleMove()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.Undone()) != 0 {
+		t.Errorf("kept %d undone steps that can't be redone", len(replayed.Undone()))
+	}
+}
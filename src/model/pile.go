@@ -0,0 +1,97 @@
+package model
+
+import "fmt"
+
+type PileKind int
+
+const (
+	PileStock PileKind = iota
+	PileWaste
+	PileFoundation
+	PileTableau
+	// PileCell comes last so that saved moves keep their meaning
+	PileCell
+)
+
+var pileKindNames = map[PileKind]string{
+	PileStock:      "stock",
+	PileWaste:      "waste",
+	PileCell:       "free cell",
+	PileFoundation: "foundation",
+	PileTableau:    "tableau",
+}
+
+func (k PileKind) String() string {
+	return pileKindNames[k]
+}
+
+// PileID addresses a single pile on the board. Index is only meaningful for
+// kinds that have more than one pile (free cells, foundations and tableau
+// columns).
+type PileID struct {
+	Kind  PileKind
+	Index int
+}
+
+var StockID = PileID{Kind: PileStock}
+var WasteID = PileID{Kind: PileWaste}
+
+func CellID(i int) PileID {
+	return PileID{Kind: PileCell, Index: i}
+}
+
+func FoundationID(i int) PileID {
+	return PileID{Kind: PileFoundation, Index: i}
+}
+
+func TableauID(i int) PileID {
+	return PileID{Kind: PileTableau, Index: i}
+}
+
+func (id PileID) String() string {
+	switch id.Kind {
+	case PileStock, PileWaste:
+		return id.Kind.String()
+	default:
+		return fmt.Sprintf("%s %d", id.Kind, id.Index+1)
+	}
+}
+
+type Pile struct {
+	Cards []Card
+}
+
+func (p *Pile) Len() int {
+	return len(p.Cards)
+}
+
+func (p *Pile) IsEmpty() bool {
+	return len(p.Cards) == 0
+}
+
+// Top returns the top card of the pile and whether the pile had one.
+func (p *Pile) Top() (Card, bool) {
+	if len(p.Cards) == 0 {
+		return Card{}, false
+	}
+	return p.Cards[len(p.Cards)-1], true
+}
+
+func (p *Pile) push(cards ...Card) {
+	p.Cards = append(p.Cards, cards...)
+}
+
+func (p *Pile) pop(count int) []Card {
+	// Copy the removed cards so later pushes cannot alias them
+	split := len(p.Cards) - count
+	removed := make([]Card, count)
+	copy(removed, p.Cards[split:])
+	p.Cards = p.Cards[:split]
+	return removed
+}
+
+func (p *Pile) clone() Pile {
+	cards := make([]Card, len(p.Cards))
+	copy(cards, p.Cards)
+	return Pile{Cards: cards}
+}
@@ -0,0 +1,121 @@
+package model
+
+const NumPyramidRows = 7
+const NumPyramidCards = NumPyramidRows * (NumPyramidRows + 1) / 2
+
+// PyramidPairSum is what the numbers of a pair of cards add up to in
+// Pyramid. Kings make it on their own.
+const PyramidPairSum = 13
+
+// Pyramid clears a pyramid of overlapping cards by removing pairs that add
+// up to thirteen, using the stock to find partners.
+type Pyramid struct {
+	rules
+}
+
+// pyramidSlots lays the tableau out row by row, each row half a card lower
+// and half a card wider than the one above.
+var pyramidSlots = func() []Slot {
+	slots := []Slot{}
+	for row := 0; row < NumPyramidRows; row++ {
+		for i := 0; i <= row; i++ {
+			slots = append(slots, Slot{
+				X: 2 + float64(NumPyramidRows-1-row)/2 + float64(i),
+				Y: float64(row) * RowStep,
+			})
+		}
+	}
+	return slots
+}()
+
+var pyramidCoveredBy = coveredBy(pyramidSlots)
+
+func (Pyramid) Name(opts Options) string {
+	return "Pyramid" + passesSuffix(opts)
+}
+
+// Layout keeps a tableau pile for each card of the pyramid, and a single
+// foundation for the cards that have been removed.
+func (Pyramid) Layout(opts Options) Layout {
+	return Layout{Stock: true, Waste: true, Foundations: 1, Tableau: NumPyramidCards}
+}
+
+// Slot puts the draw and overturned piles at the top left and the removed
+// cards at the top right, beside the pyramid.
+func (Pyramid) Slot(opts Options, id PileID) Slot {
+	switch id.Kind {
+	case PileStock:
+		return Slot{X: 0, Y: 0}
+	case PileWaste:
+		return Slot{X: 1, Y: 0}
+	case PileFoundation:
+		return Slot{X: 8, Y: 0}
+	default:
+		return pyramidSlots[id.Index]
+	}
+}
+
+func (Pyramid) Deck(opts Options) []Card {
+	return NewDeck()
+}
+
+// Deal lays the pyramid out face up, leaving the rest as the stock.
+func (Pyramid) Deal(state *State, deck []Card) {
+	state.Stock.push(deck...)
+	for i := range state.Tableau {
+		dealColumn(state, i, 1, 1)
+	}
+}
+
+func (Pyramid) Stock(opts Options) StockRules {
+	return StockRules{DrawCount: 1, Recycle: true, Passes: opts.Passes}
+}
+
+func (Pyramid) CanPickUp(s *State, id PileID, index int) bool {
+	switch id.Kind {
+	case PileTableau:
+		return isExposed(s, pyramidCoveredBy, id.Index)
+	case PileWaste:
+		return isTopCard(s, id, index)
+	default:
+		return false
+	}
+}
+
+// CanAccept takes kings onto the foundation, and a card onto a free card it
+// makes a pair with, after which both are cleared.
+func (Pyramid) CanAccept(s *State, id PileID, cards []Card) bool {
+	if len(cards) != 1 {
+		return false
+	}
+	top, ok := s.Pile(id).Top()
+	switch id.Kind {
+	case PileFoundation:
+		return cards[0].Number == King
+	case PileTableau:
+		return ok && isExposed(s, pyramidCoveredBy, id.Index) && isPair(cards[0], top)
+	case PileWaste:
+		return ok && isPair(cards[0], top)
+	default:
+		return false
+	}
+}
+
+// Cleared sends a pair home once one card has been put onto the other.
+func (Pyramid) Cleared(s *State, id PileID) int {
+	pile := s.Pile(id)
+	if id.Kind == PileFoundation || pile.Len() < 2 || !isPair(pile.Cards[pile.Len()-1], pile.Cards[pile.Len()-2]) {
+		return 0
+	}
+	return 2
+}
+
+// IsWon reports whether the pyramid has been cleared. Cards may be left in
+// the stock.
+func (Pyramid) IsWon(s *State) bool {
+	return tableauCleared(s)
+}
+
+func isPair(a, b Card) bool {
+	return int(a.Number)+int(b.Number) == PyramidPairSum
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Rand is a small SplitMix64 pseudo-random generator. Unlike the generators
+// in math/rand its output is defined entirely by this file, so a deal seed
+// produces the same shuffle on every platform and Go version.
+type Rand struct {
+	state uint64
+}
+
+func NewRand(seed uint64) *Rand {
+	return &Rand{state: seed}
+}
+
+// RandomSeed picks a fresh deal seed.
+func RandomSeed() uint64 {
+	return rand.Uint64()
+}
+
+func (r *Rand) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a uniformly distributed number in [0, n). It uses Lemire's
+// multiply-and-reject method so that no value is favoured.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("model: Intn called with non-positive n")
+	}
+	bound := uint64(n)
+	hi, lo := bits.Mul64(r.Uint64(), bound)
+	if lo < bound {
+		threshold := -bound % bound
+		for lo < threshold {
+			hi, lo = bits.Mul64(r.Uint64(), bound)
+		}
+	}
+	return int(hi)
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandMatchesSplitMix64(t *testing.T) {
+	// The reference SplitMix64 output for a seed of zero
+	want := []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f}
+	r := NewRand(0)
+	for i, w := range want {
+		if got := r.Uint64(); got != w {
+			t.Errorf("value %d is %#x, want %#x", i, got, w)
+		}
+	}
+}
+
+func pileString(p Pile) string {
+	names := make([]string, 0, len(p.Cards))
+	for _, card := range p.Cards {
+		names = append(names, card.String())
+	}
+	return strings.Join(names, " ")
+}
+
+// TestNewGameGolden pins the deals of a few seeds. Seeds are shared between
+// players and stored in saves and recordings, so a change to Rand, Shuffle
+// or the deal that alters these layouts breaks every one of them.
+func TestNewGameGolden(t *testing.T) {
+	tests := []struct {
+		seed    uint64
+		tableau []string
+		stock   string
+	}{
+		{
+			seed: 1,
+			tableau: []string{
+				"4♣",
+				"10♠ K♣",
+				"10♣ J♠ 9♦",
+				"9♣ K♥ J♦ 2♠",
+				"3♠ 8♦ 6♦ Q♦ 4♦",
+				"3♥ K♠ 8♠ 3♣ 6♠ 7♥",
+				"5♦ A♦ 2♥ 9♥ 4♥ 2♦ 6♣",
+			},
+			stock: "9♠ 7♠ Q♣ 4♠ A♥ 8♣ Q♠ J♣ 6♥ 5♣ 7♦ J♥ 3♦ 7♣ Q♥ A♠ 10♥ 5♥ 2♣ 8♥ 5♠ K♦ 10♦ A♣",
+		},
+		{
+			seed: 42,
+			tableau: []string{
+				"K♣",
+				"A♦ 9♥",
+				"2♠ 2♥ 4♦",
+				"A♣ 2♦ J♣ J♥",
+				"K♦ A♠ 8♠ 8♦ Q♠",
+				"5♣ 10♦ 10♣ 5♦ 4♥ 8♥",
+				"Q♣ 7♦ 6♠ 6♣ 4♣ 9♣ 3♥",
+			},
+			stock: "J♠ 5♥ 6♦ 3♠ 4♠ A♥ 10♥ 6♥ K♠ 2♣ Q♦ 10♠ J♦ 7♠ 8♣ 9♠ 7♥ Q♥ 5♠ K♥ 9♦ 3♣ 3♦ 7♣",
+		},
+		{
+			seed: 1234567890123,
+			tableau: []string{
+				"6♣",
+				"9♦ 4♥",
+				"6♠ Q♥ A♥",
+				"A♠ K♥ J♠ 8♣",
+				"2♥ Q♣ K♣ 3♦ 9♣",
+				"8♠ 5♠ 7♥ J♦ J♥ 2♠",
+				"7♠ 4♦ 10♥ K♠ 4♣ J♣ A♦",
+			},
+			stock: "8♥ 7♦ 4♠ Q♠ K♦ 2♣ 3♥ A♣ 10♣ 3♣ 5♣ 9♥ Q♦ 6♦ 5♦ 9♠ 6♥ 5♥ 3♠ 7♣ 10♦ 2♦ 10♠ 8♦",
+		},
+	}
+	for _, tt := range tests {
+		state := NewGame(tt.seed, DefaultOptions)
+		for i, want := range tt.tableau {
+			if got := pileString(state.Tableau[i]); got != want {
+				t.Errorf("seed %d column %d is %q, want %q", tt.seed, i+1, got, want)
+			}
+		}
+		if got := pileString(state.Stock); got != tt.stock {
+			t.Errorf("seed %d stock is %q, want %q", tt.seed, got, tt.stock)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import "fmt"
+
+// RECORDING_VERSION is bumped whenever the layout of Recording changes.
+const RECORDING_VERSION = 1
+
+// Recording is everything needed to play a game back: the deal it started
+// from and the moves that were made.
+type Recording struct {
+	Version int     `json:"version"`
+	Seed    uint64  `json:"seed"`
+	Options Options `json:"options"`
+	Moves   []Move  `json:"moves"`
+}
+
+func NewRecording(seed uint64, opts Options, moves []Move) Recording {
+	return Recording{
+		Version: RECORDING_VERSION,
+		Seed:    seed,
+		Options: opts,
+		Moves:   moves,
+	}
+}
+
+// Check plays the recording through to make sure every move is legal.
+func (r Recording) Check() error {
+	if r.Version != RECORDING_VERSION {
+		return fmt.Errorf("unsupported recording version %d", r.Version)
+	}
+	if err := r.Options.Validate(); err != nil {
+		return err
+	}
+	if err := r.Options.Game.CheckSeed(r.Seed); err != nil {
+		return err
+	}
+	state := NewGame(r.Seed, r.Options)
+	for i, move := range r.Moves {
+		if _, err := state.Apply(move); err != nil {
+			return fmt.Errorf("move %d (%s): %w", i+1, move, err)
+		}
+	}
+	return nil
+}
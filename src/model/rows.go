@@ -0,0 +1,35 @@
+package model
+
+import "math"
+
+// RowStep is how far apart overlapping rows of cards are laid out, in card
+// heights, in games like Pyramid and TriPeaks.
+const RowStep = 0.5
+
+// coveredBy works out which cards of the next row down overlap each card of
+// a layout of overlapping rows.
+func coveredBy(slots []Slot) [][]int {
+	covers := make([][]int, len(slots))
+	for i, slot := range slots {
+		for j, other := range slots {
+			if other.Y == slot.Y+RowStep && math.Abs(other.X-slot.X) == 0.5 {
+				covers[i] = append(covers[i], j)
+			}
+		}
+	}
+	return covers
+}
+
+// isExposed reports whether a tableau pile holds a card that none of the
+// cards overlapping it are left to cover.
+func isExposed(s *State, covers [][]int, index int) bool {
+	if s.Tableau[index].IsEmpty() {
+		return false
+	}
+	for _, j := range covers[index] {
+		if !s.Tableau[j].IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package model
+
+import "fmt"
+
+// NumSpiderTableau is the number of columns Spider deals into.
+const NumSpiderTableau = 10
+
+// NumSpiderFoundations is the number of foundations Spider needs. It is
+// played with two decks, so there is one for each of the eight full suits.
+const NumSpiderFoundations = 8
+
+// SpiderSuits are the suits Spider is played with at each difficulty.
+var SpiderSuits = map[int][]Suit{
+	1: {Spade},
+	2: {Spade, Heart},
+	4: Suits,
+}
+
+// Spider builds down regardless of suit, but only runs of a single suit move
+// together, and only a full suit from the king down to the ace goes home.
+type Spider struct {
+	rules
+}
+
+func (Spider) Name(opts Options) string {
+	if opts.Suits == 1 {
+		return "Spider, 1 suit"
+	}
+	return fmt.Sprintf("Spider, %d suits", opts.Suits)
+}
+
+func (Spider) Layout(opts Options) Layout {
+	return Layout{Stock: true, Foundations: NumSpiderFoundations, Tableau: NumSpiderTableau}
+}
+
+// Slot puts the draw pile at the top left and the foundations at the top
+// right, above the columns.
+func (Spider) Slot(opts Options, id PileID) Slot {
+	switch id.Kind {
+	case PileStock:
+		return Slot{X: 0, Y: 0}
+	case PileFoundation:
+		return Slot{X: float64(NumSpiderTableau - NumSpiderFoundations + id.Index), Y: 0}
+	default:
+		return Slot{X: float64(id.Index), Y: 1, Spread: true}
+	}
+}
+
+// Deck returns the 104 cards of two decks made up of the number of suits in
+// the options. With fewer suits there are more copies of each card, which
+// are told apart by their Deck.
+func (Spider) Deck(opts Options) []Card {
+	suits := SpiderSuits[opts.Suits]
+	deck := make([]Card, 0, 2*len(Suits)*len(Numbers))
+	for d := 0; len(suits) > 0 && d < 2*len(Suits)/len(suits); d++ {
+		for _, suit := range suits {
+			for _, number := range Numbers {
+				deck = append(deck, Card{Number: number, Suit: suit, Deck: d})
+			}
+		}
+	}
+	return deck
+}
+
+// Deal gives the first four columns six cards and the rest five, with only
+// the last card of each face up. The rest becomes the stock.
+func (Spider) Deal(state *State, deck []Card) {
+	state.Stock.push(deck...)
+	for i := range state.Tableau {
+		count := 5
+		if i < 4 {
+			count = 6
+		}
+		dealColumn(state, i, count, 1)
+	}
+}
+
+// Stock deals a card onto every column at once.
+func (Spider) Stock(opts Options) StockRules {
+	return StockRules{}
+}
+
+func (Spider) CanPickUp(s *State, id PileID, index int) bool {
+	cards := s.Pile(id).Cards[index:]
+	return id.Kind == PileTableau && cards[0].FaceUp && isSuitRun(cards)
+}
+
+// CanAccept only takes cards onto the columns. The foundations are filled by
+// Cleared.
+func (Spider) CanAccept(s *State, id PileID, cards []Card) bool {
+	if id.Kind != PileTableau {
+		return false
+	}
+	top, ok := s.Pile(id).Top()
+	return !ok || (top.FaceUp && cards[0].Number.IsOneLessThan(top.Number))
+}
+
+// Cleared sends a full suit at the end of a column home.
+func (Spider) Cleared(s *State, id PileID) int {
+	pile := s.Pile(id)
+	if id.Kind != PileTableau || pile.Len() < len(Numbers) {
+		return 0
+	}
+	run := pile.Cards[pile.Len()-len(Numbers):]
+	if run[0].Number != King || !run[0].FaceUp || !isSuitRun(run) {
+		return 0
+	}
+	return len(run)
+}
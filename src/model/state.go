@@ -0,0 +1,179 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Options are the rule choices a game is dealt with.
+type Options struct {
+	Game Game
+	// DrawCount is the number of cards turned from the stock at once in
+	// Klondike, 1 or 3.
+	DrawCount int
+	// Suits is the number of different suits Spider is played with, 1, 2 or
+	// 4. Fewer suits make for an easier game.
+	Suits int
+	// Passes limits how many times the stock can be gone through in games
+	// that turn the waste back over. Zero means there is no limit.
+	Passes int
+}
+
+var DefaultOptions = Options{
+	DrawCount: 1,
+	Suits:     1,
+}
+
+func (o Options) Variant() Variant {
+	return o.Game.Variant()
+}
+
+// Name describes the rules, e.g. to group statistics by the kind of game.
+func (o Options) Name() string {
+	return o.Variant().Name(o)
+}
+
+// Validate checks that the options describe a game that can be dealt, e.g.
+// after reading them from a file.
+func (o Options) Validate() error {
+	if _, ok := variants[o.Game]; !ok {
+		return fmt.Errorf("unknown game %d", o.Game)
+	}
+	if o.DrawCount != 1 && o.DrawCount != 3 {
+		return fmt.Errorf("invalid draw count %d, must be 1 or 3", o.DrawCount)
+	}
+	if _, ok := SpiderSuits[o.Suits]; !ok {
+		return fmt.Errorf("invalid number of suits %d, must be 1, 2 or 4", o.Suits)
+	}
+	if o.Passes < 0 {
+		return fmt.Errorf("invalid number of passes %d", o.Passes)
+	}
+	return nil
+}
+
+// State is the complete, rendering-independent state of a game.
+type State struct {
+	Options Options
+	// Seed is the seed the deck was shuffled with, if it came from NewGame.
+	Seed uint64
+	// Recycles counts the times the waste has been turned back over onto the
+	// stock, which is one less than the passes made through it.
+	Recycles int
+
+	Stock       Pile
+	Waste       Pile
+	Cells       []Pile
+	Foundations []Pile
+	Tableau     []Pile
+}
+
+func (s *State) Pile(id PileID) *Pile {
+	switch id.Kind {
+	case PileStock:
+		return &s.Stock
+	case PileWaste:
+		return &s.Waste
+	case PileCell:
+		if id.Index >= 0 && id.Index < len(s.Cells) {
+			return &s.Cells[id.Index]
+		}
+	case PileFoundation:
+		if id.Index >= 0 && id.Index < len(s.Foundations) {
+			return &s.Foundations[id.Index]
+		}
+	case PileTableau:
+		if id.Index >= 0 && id.Index < len(s.Tableau) {
+			return &s.Tableau[id.Index]
+		}
+	}
+	return nil
+}
+
+// PileIDs lists every pile on the board in a stable order.
+func (s *State) PileIDs() []PileID {
+	layout := s.Options.Variant().Layout(s.Options)
+	layout.Cells = len(s.Cells)
+	layout.Foundations = len(s.Foundations)
+	layout.Tableau = len(s.Tableau)
+	return layout.PileIDs()
+}
+
+func (s *State) Clone() *State {
+	clone := &State{
+		Options:     s.Options,
+		Seed:        s.Seed,
+		Recycles:    s.Recycles,
+		Stock:       s.Stock.clone(),
+		Waste:       s.Waste.clone(),
+		Cells:       make([]Pile, len(s.Cells)),
+		Foundations: make([]Pile, len(s.Foundations)),
+		Tableau:     make([]Pile, len(s.Tableau)),
+	}
+	for i := range s.Cells {
+		clone.Cells[i] = s.Cells[i].clone()
+	}
+	for i := range s.Foundations {
+		clone.Foundations[i] = s.Foundations[i].clone()
+	}
+	for i := range s.Tableau {
+		clone.Tableau[i] = s.Tableau[i].clone()
+	}
+	return clone
+}
+
+// Equal reports whether two states are the same game with every card in the
+// same place, facing the same way.
+func (s *State) Equal(other *State) bool {
+	if s.Options != other.Options || s.Seed != other.Seed || s.Recycles != other.Recycles {
+		return false
+	}
+	if len(s.Cells) != len(other.Cells) || len(s.Foundations) != len(other.Foundations) || len(s.Tableau) != len(other.Tableau) {
+		return false
+	}
+	for _, id := range s.PileIDs() {
+		if !slices.Equal(s.Pile(id).Cards, other.Pile(id).Cards) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *State) IsWon() bool {
+	return s.Options.Variant().IsWon(s)
+}
+
+// CheckCards verifies that the state holds exactly the cards it was dealt
+// with, e.g. after loading it from disk.
+func (s *State) CheckCards() error {
+	layout := s.Options.Variant().Layout(s.Options)
+	if len(s.Foundations) != layout.Foundations || len(s.Tableau) != layout.Tableau || len(s.Cells) != layout.Cells {
+		return fmt.Errorf(
+			"expected %d foundations, %d tableau piles and %d free cells",
+			layout.Foundations, layout.Tableau, layout.Cells,
+		)
+	}
+	if (!layout.Stock && !s.Stock.IsEmpty()) || (!layout.Waste && !s.Waste.IsEmpty()) {
+		return fmt.Errorf("%s has no stock or waste to hold cards", s.Options.Name())
+	}
+
+	expected := map[CardID]bool{}
+	for _, card := range s.Options.Variant().Deck(s.Options) {
+		expected[card.ID()] = true
+	}
+	seen := map[CardID]bool{}
+	for _, id := range s.PileIDs() {
+		for _, card := range s.Pile(id).Cards {
+			if !expected[card.ID()] {
+				return fmt.Errorf("unexpected card %s (%d, %q, deck %d) in %s", card, card.Number, card.Suit, card.Deck, id)
+			}
+			if seen[card.ID()] {
+				return fmt.Errorf("duplicate card %s in %s", card, id)
+			}
+			seen[card.ID()] = true
+		}
+	}
+	if len(seen) != len(expected) {
+		return fmt.Errorf("expected %d cards, found %d", len(expected), len(seen))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package model
+
+type Suit string
+
+const (
+	Spade   Suit = "spade"
+	Diamond Suit = "diamond"
+	Club    Suit = "club"
+	Heart   Suit = "heart"
+)
+
+var Suits = []Suit{Heart, Diamond, Club, Spade}
+
+var SuitSymbols = map[Suit]string{
+	Spade:   "♠",
+	Diamond: "♦",
+	Club:    "♣",
+	Heart:   "♥",
+}
+
+func (s Suit) IsRed() bool {
+	return s == Heart || s == Diamond
+}
+
+func (s Suit) IsOppositeColor(other Suit) bool {
+	switch s {
+	case Spade, Club:
+		return other == Heart || other == Diamond
+	case Heart, Diamond:
+		return other == Spade || other == Club
+	default:
+		return false
+	}
+}
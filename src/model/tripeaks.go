@@ -0,0 +1,112 @@
+package model
+
+const NumTriPeaksCards = 28
+
+// TriPeaks clears three overlapping peaks of cards by playing uncovered
+// cards onto the waste, one rank above or below the card showing there,
+// turning cards over as they are uncovered. The stock is only gone through
+// once.
+type TriPeaks struct {
+	rules
+}
+
+// triPeaksSlots lays the tableau out row by row: the tips of the three peaks,
+// then the two rows widening beneath them and a full row of ten at the
+// bottom, each row overlapping the one above by half a card.
+var triPeaksSlots = func() []Slot {
+	slots := []Slot{}
+	for peak := 0; peak < 3; peak++ {
+		slots = append(slots, Slot{X: 3*float64(peak) + 1.5, Y: 0})
+	}
+	for peak := 0; peak < 3; peak++ {
+		for i := 0; i < 2; i++ {
+			slots = append(slots, Slot{X: 3*float64(peak) + float64(i) + 1, Y: RowStep})
+		}
+	}
+	for i := 0; i < 9; i++ {
+		slots = append(slots, Slot{X: float64(i) + 0.5, Y: 2 * RowStep})
+	}
+	for i := 0; i < 10; i++ {
+		slots = append(slots, Slot{X: float64(i), Y: 3 * RowStep})
+	}
+	return slots
+}()
+
+var triPeaksCoveredBy = coveredBy(triPeaksSlots)
+
+func (TriPeaks) Name(opts Options) string {
+	return "TriPeaks"
+}
+
+// Layout keeps a tableau pile for each card of the peaks.
+func (TriPeaks) Layout(opts Options) Layout {
+	return Layout{Stock: true, Waste: true, Tableau: NumTriPeaksCards}
+}
+
+// Slot puts the draw and overturned piles underneath the peaks.
+func (TriPeaks) Slot(opts Options, id PileID) Slot {
+	switch id.Kind {
+	case PileStock:
+		return Slot{X: 3.5, Y: 5 * RowStep}
+	case PileWaste:
+		return Slot{X: 5, Y: 5 * RowStep}
+	default:
+		return triPeaksSlots[id.Index]
+	}
+}
+
+func (TriPeaks) Deck(opts Options) []Card {
+	return NewDeck()
+}
+
+// Deal lays out the peaks with only the bottom row face up, and starts the
+// waste with a card from the stock.
+func (TriPeaks) Deal(state *State, deck []Card) {
+	state.Stock.push(deck...)
+	for i := range state.Tableau {
+		faceUp := 0
+		if len(triPeaksCoveredBy[i]) == 0 {
+			faceUp = 1
+		}
+		dealColumn(state, i, 1, faceUp)
+	}
+	turnOverWaste(state)
+}
+
+func (TriPeaks) Stock(opts Options) StockRules {
+	return StockRules{DrawCount: 1}
+}
+
+func (TriPeaks) CanPickUp(s *State, id PileID, index int) bool {
+	return id.Kind == PileTableau && isExposed(s, triPeaksCoveredBy, id.Index)
+}
+
+// CanAccept takes single cards onto the waste, going round the corner from
+// king to ace.
+func (TriPeaks) CanAccept(s *State, id PileID, cards []Card) bool {
+	top, ok := s.Pile(id).Top()
+	return id.Kind == PileWaste && len(cards) == 1 && ok && isNeighbour(cards[0], top, true)
+}
+
+// Uncovered finds the cards that taking a card off the peaks leaves with
+// nothing on top of them.
+func (TriPeaks) Uncovered(s *State, from PileID) []PileID {
+	if from.Kind != PileTableau {
+		return nil
+	}
+	uncovered := []PileID{}
+	for i, covers := range triPeaksCoveredBy {
+		for _, j := range covers {
+			if j == from.Index && isExposed(s, triPeaksCoveredBy, i) {
+				uncovered = append(uncovered, TableauID(i))
+			}
+		}
+	}
+	return uncovered
+}
+
+// IsWon reports whether the peaks have been cleared. Cards may be left in
+// the stock.
+func (TriPeaks) IsWon(s *State) bool {
+	return tableauCleared(s)
+}
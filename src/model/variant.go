@@ -0,0 +1,311 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// Variant is a set of solitaire rules: how the piles are laid out and dealt,
+// which cards each pile takes, what the stock does and when the game is won.
+// Every rule takes the options the game was dealt with, or a state that
+// carries them.
+type Variant interface {
+	// Name describes the game, e.g. to group statistics by the kind of game.
+	Name(opts Options) string
+	// Layout counts the piles of each kind the game is played with.
+	Layout(opts Options) Layout
+	// Slot places a pile on the board.
+	Slot(opts Options, id PileID) Slot
+	// Deck returns every card the game is played with, face down.
+	Deck(opts Options) []Card
+	// Deal lays a face down deck out onto the empty piles of a new game.
+	Deal(state *State, deck []Card)
+	// Stock describes what drawing from the stock does.
+	Stock(opts Options) StockRules
+
+	// CanPickUp reports whether the cards of a pile from index upwards may
+	// be lifted as a unit.
+	CanPickUp(s *State, id PileID, index int) bool
+	// CanAccept reports whether the given cards may be placed onto a pile.
+	CanAccept(s *State, id PileID, cards []Card) bool
+	// MaxMoveCount returns how many cards can be moved onto a pile at once.
+	MaxMoveCount(s *State, to PileID) int
+	// Cleared returns how many cards from the top of a pile go to a
+	// foundation of their own accord once cards have been put on it.
+	Cleared(s *State, id PileID) int
+	// Uncovered lists the piles, other than the one cards were taken from,
+	// whose top card is freed by taking cards off a pile.
+	Uncovered(s *State, from PileID) []PileID
+
+	// CanAutoFinish reports whether the game can be played out to the end by
+	// sending cards to the foundations one at a time, lowest first.
+	CanAutoFinish(s *State) bool
+	IsWon(s *State) bool
+}
+
+// NumberedDeals is implemented by variants that number their deals with a
+// scheme of their own instead of shuffling by seed, like Microsoft FreeCell.
+type NumberedDeals interface {
+	// NumberedDeck returns the deck of a deal number, in the order the
+	// variant deals it.
+	NumberedDeck(number uint64) []Card
+	RandomDealNumber() uint64
+	// NumDeals is how many deals there are, numbered from 1.
+	NumDeals() uint64
+}
+
+// Layout counts the piles of each kind a game is played with.
+type Layout struct {
+	Stock       bool
+	Waste       bool
+	Cells       int
+	Foundations int
+	Tableau     int
+}
+
+// PileIDs lists every pile of the layout in a stable order, the order the
+// piles are drawn in.
+func (l Layout) PileIDs() []PileID {
+	ids := []PileID{}
+	if l.Stock {
+		ids = append(ids, StockID)
+	}
+	if l.Waste {
+		ids = append(ids, WasteID)
+	}
+	for i := 0; i < l.Cells; i++ {
+		ids = append(ids, CellID(i))
+	}
+	for i := 0; i < l.Foundations; i++ {
+		ids = append(ids, FoundationID(i))
+	}
+	for i := 0; i < l.Tableau; i++ {
+		ids = append(ids, TableauID(i))
+	}
+	return ids
+}
+
+// Slot places a pile on the board, measured in card-sized steps from the top
+// left corner.
+type Slot struct {
+	X float64
+	Y float64
+	// Spread fans the cards of the pile out downwards so that all of them
+	// show, as in a tableau column.
+	Spread bool
+}
+
+// StockRules describe what drawing from the stock does.
+type StockRules struct {
+	// DrawCount is the number of cards turned onto the waste at once. Zero
+	// deals a card onto every tableau column instead.
+	DrawCount int
+	// Recycle allows the waste to be turned back over onto the stock once
+	// the stock has run out.
+	Recycle bool
+	// Passes is the number of times the stock can be gone through when it
+	// can be recycled, or zero for no limit.
+	Passes int
+}
+
+// Game identifies the kind of solitaire being played.
+type Game int
+
+const (
+	GameKlondike Game = iota
+	GameFreeCell
+	GameSpider
+	GameYukon
+	GameGolf
+	GamePyramid
+	GameTriPeaks
+)
+
+var variants = map[Game]Variant{
+	GameKlondike: Klondike{},
+	GameFreeCell: FreeCell{},
+	GameSpider:   Spider{},
+	GameYukon:    Yukon{},
+	GameGolf:     Golf{},
+	GamePyramid:  Pyramid{},
+	GameTriPeaks: TriPeaks{},
+}
+
+var gameNames = map[Game]string{
+	GameKlondike: "klondike",
+	GameFreeCell: "freecell",
+	GameSpider:   "spider",
+	GameYukon:    "yukon",
+	GameGolf:     "golf",
+	GamePyramid:  "pyramid",
+	GameTriPeaks: "tripeaks",
+}
+
+func (g Game) String() string {
+	return gameNames[g]
+}
+
+// ParseGame looks up a game by the name String gives it.
+func ParseGame(name string) (Game, bool) {
+	for game, gameName := range gameNames {
+		if gameName == name {
+			return game, true
+		}
+	}
+	return 0, false
+}
+
+// Variant returns the rules of the game, falling back to Klondike for games
+// this version does not know.
+func (g Game) Variant() Variant {
+	if variant, ok := variants[g]; ok {
+		return variant
+	}
+	return variants[GameKlondike]
+}
+
+// RandomSeed picks a seed for a new deal. Variants with numbered deals keep
+// to their own range of numbers.
+func (g Game) RandomSeed() uint64 {
+	if numbered, ok := g.Variant().(NumberedDeals); ok {
+		return numbered.RandomDealNumber()
+	}
+	return RandomSeed()
+}
+
+// CheckSeed checks that a seed picks one of the deals of the game. Games
+// with numbered deals only have the deals they number.
+func (g Game) CheckSeed(seed uint64) error {
+	if numbered, ok := g.Variant().(NumberedDeals); ok && (seed < 1 || seed > numbered.NumDeals()) {
+		return fmt.Errorf("%s deals are numbered 1 to %d, not %d", g, numbered.NumDeals(), seed)
+	}
+	return nil
+}
+
+// passesSuffix adds the pass limit to the name of a game, so that games
+// played with different limits are told apart.
+func passesSuffix(opts Options) string {
+	switch opts.Passes {
+	case 0:
+		return ""
+	case 1:
+		return ", 1 pass"
+	default:
+		return fmt.Sprintf(", %d passes", opts.Passes)
+	}
+}
+
+// rules provides the behaviour most variants share, for embedding.
+type rules struct{}
+
+func (rules) MaxMoveCount(s *State, to PileID) int {
+	return math.MaxInt
+}
+
+func (rules) Cleared(s *State, id PileID) int {
+	return 0
+}
+
+func (rules) Uncovered(s *State, from PileID) []PileID {
+	return nil
+}
+
+func (rules) CanAutoFinish(s *State) bool {
+	return false
+}
+
+// IsWon reports whether every card has been played to the foundations.
+func (rules) IsWon(s *State) bool {
+	for _, id := range s.PileIDs() {
+		if id.Kind != PileFoundation && !s.Pile(id).IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// isTopCard reports whether index is the top card of a pile.
+func isTopCard(s *State, id PileID, index int) bool {
+	return index == s.Pile(id).Len()-1
+}
+
+// isAlternatingRun reports whether the cards descend one at a time in
+// alternating colors.
+func isAlternatingRun(cards []Card) bool {
+	for i := 1; i < len(cards); i++ {
+		if !cards[i].Suit.IsOppositeColor(cards[i-1].Suit) || !cards[i].Number.IsOneLessThan(cards[i-1].Number) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSuitRun reports whether the cards descend one at a time in a single suit.
+func isSuitRun(cards []Card) bool {
+	for i := 1; i < len(cards); i++ {
+		if cards[i].Suit != cards[i-1].Suit || !cards[i].Number.IsOneLessThan(cards[i-1].Number) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildsOnAlternating reports whether cards can go onto a tableau column
+// built down in alternating colors, where an empty column takes whatever
+// emptyOK allows.
+func buildsOnAlternating(pile *Pile, cards []Card, emptyOK func(Card) bool) bool {
+	bottom := cards[0]
+	top, ok := pile.Top()
+	if !ok {
+		return emptyOK(bottom)
+	}
+	return top.FaceUp && top.Suit.IsOppositeColor(bottom.Suit) && bottom.Number.IsOneLessThan(top.Number)
+}
+
+// buildsOnFoundation reports whether cards can go onto a foundation built
+// one card at a time, from the ace up in a single suit.
+func buildsOnFoundation(pile *Pile, cards []Card) bool {
+	if len(cards) > 1 {
+		return false
+	}
+	top, ok := pile.Top()
+	if !ok {
+		return cards[0].Number == Ace
+	}
+	return cards[0].Suit == top.Suit && cards[0].Number.IsOneMoreThan(top.Number)
+}
+
+// isKing allows a king, and only a king, to start an empty column.
+func isKing(card Card) bool {
+	return card.Number == King
+}
+
+// anyCard allows any card to start an empty column.
+func anyCard(card Card) bool {
+	return true
+}
+
+// dealColumn moves count cards from the stock onto a tableau column, turning
+// the last faceUp of them face up.
+func dealColumn(state *State, column int, count int, faceUp int) {
+	cards := state.Stock.pop(count)
+	setFaceUp(cards[max(count-faceUp, 0):], true)
+	state.Tableau[column].push(cards...)
+}
+
+// canFinishByFoundations reports whether the stock and waste are empty and
+// every tableau column is face up and descends towards its top card, so that
+// the lowest card left is always free to go home.
+func canFinishByFoundations(s *State) bool {
+	if !s.Stock.IsEmpty() || !s.Waste.IsEmpty() {
+		return false
+	}
+	for _, pile := range s.Tableau {
+		for i, card := range pile.Cards {
+			if !card.FaceUp || (i > 0 && card.Number > pile.Cards[i-1].Number) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package model
+
+// Yukon deals the whole deck into Klondike's columns, mostly face up. Any
+// face up card can be moved along with everything on top of it, in sequence
+// or not.
+type Yukon struct {
+	rules
+}
+
+func (Yukon) Name(opts Options) string {
+	return "Yukon"
+}
+
+func (Yukon) Layout(opts Options) Layout {
+	return Layout{Foundations: NumFoundations, Tableau: NumTableau}
+}
+
+// Slot puts the foundations at the top right, above the columns, where they
+// are in Klondike.
+func (Yukon) Slot(opts Options, id PileID) Slot {
+	if id.Kind == PileFoundation {
+		return Slot{X: float64(3 + id.Index), Y: 0}
+	}
+	return Slot{X: float64(id.Index), Y: 1, Spread: true}
+}
+
+func (Yukon) Deck(opts Options) []Card {
+	return NewDeck()
+}
+
+// Deal gives the first column a single card and column i i face down cards
+// with five face up cards on top.
+func (Yukon) Deal(state *State, deck []Card) {
+	state.Stock.push(deck...)
+	dealColumn(state, 0, 1, 1)
+	for i := 1; i < len(state.Tableau); i++ {
+		dealColumn(state, i, i+5, 5)
+	}
+}
+
+func (Yukon) Stock(opts Options) StockRules {
+	return StockRules{}
+}
+
+func (Yukon) CanPickUp(s *State, id PileID, index int) bool {
+	switch id.Kind {
+	case PileTableau:
+		return s.Pile(id).Cards[index].FaceUp
+	case PileFoundation:
+		return isTopCard(s, id, index)
+	default:
+		return false
+	}
+}
+
+func (Yukon) CanAccept(s *State, id PileID, cards []Card) bool {
+	switch id.Kind {
+	case PileTableau:
+		return buildsOnAlternating(s.Pile(id), cards, isKing)
+	case PileFoundation:
+		return buildsOnFoundation(s.Pile(id), cards)
+	default:
+		return false
+	}
+}
+
+func (Yukon) CanAutoFinish(s *State) bool {
+	return canFinishByFoundations(s)
+}
@@ -0,0 +1,47 @@
+package scoring
+
+import (
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+// Scorer is a scoring system. Scorers only describe how points are earned;
+// the running score of a game is kept by a Tally.
+type Scorer interface {
+	// ID is the name New knows the scorer by.
+	ID() string
+	Name() string
+	// Start is the score a new game begins with.
+	Start() int
+	// Step returns the points earned (or lost) by an applied step.
+	Step(step model.Step, state *model.State) int
+	// Recycle returns the points earned (or lost) by turning the waste back
+	// over onto the stock, on top of those for the step itself.
+	Recycle(state *model.State) int
+	// Passes is the limit on passes through the stock the system is played
+	// with, or zero for no limit.
+	Passes(opts model.Options) int
+	// Elapsed returns the adjustment for the time spent on the game so far.
+	Elapsed(elapsed time.Duration) int
+	// WinBonus returns the bonus awarded for winning in the given time.
+	WinBonus(elapsed time.Duration) int
+	// Clamp limits a score to the range the system allows.
+	Clamp(points int) int
+	// GameOver is told the final score of every finished or abandoned game.
+	GameOver(final int)
+}
+
+// New returns the scorer with the given name, or nil if there is none.
+func New(name string) Scorer {
+	switch name {
+	case "standard":
+		return &Standard{}
+	case "vegas":
+		return &Vegas{}
+	case "vegas-cumulative":
+		return &Vegas{Cumulative: true}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package scoring
+
+import (
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+// Standard is the scoring system of Windows Solitaire.
+type Standard struct{}
+
+func (s *Standard) ID() string {
+	return "standard"
+}
+
+func (s *Standard) Name() string {
+	return "Standard"
+}
+
+func (s *Standard) Start() int {
+	return 0
+}
+
+func (s *Standard) Step(step model.Step, state *model.State) int {
+	points := 0
+	m := step.Move
+	if m.Kind == model.MoveCards {
+		switch {
+		case m.From.Kind == model.PileWaste && m.To.Kind == model.PileTableau:
+			points += 5
+		case m.From.Kind != model.PileFoundation && m.To.Kind == model.PileFoundation:
+			points += 10
+		case m.From.Kind == model.PileFoundation && m.To.Kind == model.PileTableau:
+			points -= 15
+		}
+	}
+
+	// Turning over a tableau card is worth points of its own
+	if step.Flipped {
+		points += 5
+	}
+
+	// Every card the game clears on its own, like a suit in Spider, counts
+	// as played home
+	for _, completion := range step.Completed {
+		points += 10 * completion.Count
+		if completion.Flipped {
+			points += 5
+		}
+	}
+	return points
+}
+
+func (s *Standard) Recycle(state *model.State) int {
+	// Going through the stock again is expensive, especially one card at a time
+	if state.Options.DrawCount == 1 {
+		return -100
+	}
+	return -20
+}
+
+func (s *Standard) Passes(opts model.Options) int {
+	return 0
+}
+
+func (s *Standard) Elapsed(elapsed time.Duration) int {
+	// Two points are lost for every ten seconds of play
+	return -2 * int(elapsed/(10*time.Second))
+}
+
+func (s *Standard) WinBonus(elapsed time.Duration) int {
+	// Games won in under 30 seconds don't earn a bonus
+	seconds := int(elapsed / time.Second)
+	if seconds < 30 {
+		return 0
+	}
+	return 700000 / seconds
+}
+
+func (s *Standard) Clamp(points int) int {
+	return max(points, 0)
+}
+
+func (s *Standard) GameOver(final int) {}
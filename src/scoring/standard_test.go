@@ -0,0 +1,70 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+func TestStandardStep(t *testing.T) {
+	completedSuit := model.Step{
+		Move:      model.CardsMove(model.TableauID(0), model.TableauID(1), 1),
+		Completed: []model.Completion{{Count: 13, Flipped: true}},
+	}
+	tests := []struct {
+		name string
+		step model.Step
+		want int
+	}{
+		{"waste to tableau", wasteToTableau, 5},
+		{"tableau to foundation", tableauToFound, 10},
+		{"foundation to tableau", foundToTableau, -15},
+		{"turning a card over", flippingMove, 5},
+		{"draw", model.Step{Move: model.DrawMove()}, 0},
+		{"suit cleared", completedSuit, 10*13 + 5},
+	}
+	scorer := &Standard{}
+	for _, tt := range tests {
+		if got := scorer.Step(tt.step, drawOneState); got != tt.want {
+			t.Errorf("%s scores %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStandardRecycle(t *testing.T) {
+	scorer := &Standard{}
+	if got := scorer.Recycle(drawOneState); got != -100 {
+		t.Errorf("recycling when drawing one scores %d, want -100", got)
+	}
+	if got := scorer.Recycle(drawThreeState); got != -20 {
+		t.Errorf("recycling when drawing three scores %d, want -20", got)
+	}
+	if got := scorer.Passes(drawOneState.Options); got != 0 {
+		t.Errorf("standard scoring limits passes to %d, want no limit", got)
+	}
+}
+
+func TestStandardTime(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		penalty int
+		bonus   int
+	}{
+		{0, 0, 0},
+		{9 * time.Second, 0, 0},
+		{29 * time.Second, -4, 0},
+		{30 * time.Second, -6, 700000 / 30},
+		{100 * time.Second, -20, 7000},
+		{10 * time.Minute, -120, 700000 / 600},
+	}
+	scorer := &Standard{}
+	for _, tt := range tests {
+		if got := scorer.Elapsed(tt.elapsed); got != tt.penalty {
+			t.Errorf("time penalty after %s is %d, want %d", tt.elapsed, got, tt.penalty)
+		}
+		if got := scorer.WinBonus(tt.elapsed); got != tt.bonus {
+			t.Errorf("win bonus after %s is %d, want %d", tt.elapsed, got, tt.bonus)
+		}
+	}
+}
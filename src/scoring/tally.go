@@ -0,0 +1,76 @@
+package scoring
+
+import (
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+// Tally keeps the running score of a single game. It remembers what every
+// step was worth so that undoing a step also takes back its points.
+type Tally struct {
+	scorer Scorer
+	points int
+	deltas []int
+}
+
+func NewTally(scorer Scorer) *Tally {
+	return &Tally{
+		scorer: scorer,
+		points: scorer.Start(),
+	}
+}
+
+// TallyRecord is the saved form of a tally.
+type TallyRecord struct {
+	Points int   `json:"points"`
+	Deltas []int `json:"deltas"`
+}
+
+func RestoreTally(scorer Scorer, record TallyRecord) *Tally {
+	return &Tally{
+		scorer: scorer,
+		points: record.Points,
+		deltas: record.Deltas,
+	}
+}
+
+func (t *Tally) Record() TallyRecord {
+	return TallyRecord{
+		Points: t.points,
+		Deltas: t.deltas,
+	}
+}
+
+func (t *Tally) Scorer() Scorer {
+	return t.scorer
+}
+
+func (t *Tally) Apply(step model.Step, state *model.State) {
+	// Record the change actually made, which may be less than the raw points after clamping
+	delta := t.scorer.Step(step, state)
+	if step.Move.Kind == model.MoveRecycle {
+		delta += t.scorer.Recycle(state)
+	}
+	next := t.scorer.Clamp(t.points + delta)
+	t.deltas = append(t.deltas, next-t.points)
+	t.points = next
+}
+
+func (t *Tally) Undo() {
+	if len(t.deltas) == 0 {
+		return
+	}
+	t.points -= t.deltas[len(t.deltas)-1]
+	t.deltas = t.deltas[:len(t.deltas)-1]
+}
+
+// Points returns the current score, including any adjustment for time.
+func (t *Tally) Points(elapsed time.Duration) int {
+	return t.scorer.Clamp(t.points + t.scorer.Elapsed(elapsed))
+}
+
+// Final returns the score of a won game, including the win bonus.
+func (t *Tally) Final(elapsed time.Duration) int {
+	return t.scorer.Clamp(t.Points(elapsed) + t.scorer.WinBonus(elapsed))
+}
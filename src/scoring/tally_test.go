@@ -0,0 +1,75 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+var (
+	wasteToTableau = model.Step{Move: model.CardsMove(model.WasteID, model.TableauID(0), 1)}
+	tableauToFound = model.Step{Move: model.CardsMove(model.TableauID(0), model.FoundationID(0), 1)}
+	foundToTableau = model.Step{Move: model.CardsMove(model.FoundationID(0), model.TableauID(0), 1)}
+	flippingMove   = model.Step{Move: model.CardsMove(model.TableauID(0), model.TableauID(1), 1), Flipped: true}
+	recycle        = model.Step{Move: model.RecycleMove()}
+	drawOneState   = &model.State{Options: model.Options{DrawCount: 1}}
+	drawThreeState = &model.State{Options: model.Options{DrawCount: 3}}
+)
+
+func TestTallyClampsAndUndoes(t *testing.T) {
+	tally := NewTally(&Standard{})
+	tests := []struct {
+		step model.Step
+		want int
+	}{
+		// A standard score never drops below zero, so taking a card back
+		// from the foundations first costs nothing
+		{foundToTableau, 0},
+		{wasteToTableau, 5},
+		{tableauToFound, 15},
+		{foundToTableau, 0},
+		{flippingMove, 5},
+		// The recycle costs 20 points, of which only 5 are left to lose
+		{recycle, 0},
+	}
+	for i, tt := range tests {
+		tally.Apply(tt.step, drawThreeState)
+		if got := tally.Points(0); got != tt.want {
+			t.Errorf("after step %d the score is %d, want %d", i+1, got, tt.want)
+		}
+	}
+
+	// Undoing takes back only what each step actually changed
+	for i := len(tests) - 1; i > 0; i-- {
+		tally.Undo()
+		if got, want := tally.Points(0), tests[i-1].want; got != want {
+			t.Errorf("after undoing step %d the score is %d, want %d", i+1, got, want)
+		}
+	}
+	tally.Undo()
+	tally.Undo()
+	if got := tally.Points(0); got != 0 {
+		t.Errorf("after undoing everything the score is %d, want 0", got)
+	}
+}
+
+func TestTallyRestores(t *testing.T) {
+	tally := NewTally(&Vegas{})
+	tally.Apply(tableauToFound, drawOneState)
+	restored := RestoreTally(&Vegas{}, tally.Record())
+	restored.Undo()
+	if got, want := restored.Points(0), -VEGAS_BUY_IN; got != want {
+		t.Errorf("undoing a restored step left %d, want %d", got, want)
+	}
+}
+
+func TestTallyFinal(t *testing.T) {
+	tally := NewTally(&Standard{})
+	tally.Apply(tableauToFound, drawOneState)
+	// The time penalty can only take the ten points earned down to zero,
+	// before the bonus of 700000 divided by the seconds taken
+	if got, want := tally.Final(100*time.Second), 7000; got != want {
+		t.Errorf("final score is %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package scoring
+
+import (
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+const VEGAS_BUY_IN = 52
+const VEGAS_CARD_VALUE = 5
+
+// Vegas is the casino scoring system: every game is bought for $52 and
+// each card played to a foundation pays $5 back.
+type Vegas struct {
+	// Cumulative carries the bankroll over from one game to the next. It is
+	// kept with the statistics to carry it over between sessions too.
+	Cumulative bool
+
+	bankroll int
+}
+
+func (v *Vegas) ID() string {
+	if v.Cumulative {
+		return "vegas-cumulative"
+	}
+	return "vegas"
+}
+
+func (v *Vegas) Name() string {
+	if v.Cumulative {
+		return "Vegas (cumulative)"
+	}
+	return "Vegas"
+}
+
+func (v *Vegas) Start() int {
+	return v.bankroll - VEGAS_BUY_IN
+}
+
+func (v *Vegas) Step(step model.Step, state *model.State) int {
+	// Every card the game clears on its own, like a suit in Spider, counts
+	// as played home
+	points := 0
+	for _, completion := range step.Completed {
+		points += VEGAS_CARD_VALUE * completion.Count
+	}
+
+	m := step.Move
+	if m.Kind != model.MoveCards {
+		return points
+	}
+	switch {
+	case m.To.Kind == model.PileFoundation && m.From.Kind != model.PileFoundation:
+		return points + VEGAS_CARD_VALUE
+	case m.From.Kind == model.PileFoundation && m.To.Kind != model.PileFoundation:
+		return points - VEGAS_CARD_VALUE
+	default:
+		return points
+	}
+}
+
+func (v *Vegas) Recycle(state *model.State) int {
+	return 0
+}
+
+// Passes allows a single pass through the stock when drawing one card at a
+// time, and three when drawing three.
+func (v *Vegas) Passes(opts model.Options) int {
+	if opts.DrawCount == 1 {
+		return 1
+	}
+	return 3
+}
+
+func (v *Vegas) Elapsed(elapsed time.Duration) int {
+	return 0
+}
+
+func (v *Vegas) WinBonus(elapsed time.Duration) int {
+	return 0
+}
+
+func (v *Vegas) Clamp(points int) int {
+	return points
+}
+
+// Bankroll is the money carried over from the games played so far, which
+// is only kept when the bankroll is cumulative.
+func (v *Vegas) Bankroll() int {
+	return v.bankroll
+}
+
+// SetBankroll carries on with the bankroll of an earlier session.
+func (v *Vegas) SetBankroll(bankroll int) {
+	if v.Cumulative {
+		v.bankroll = bankroll
+	}
+}
+
+func (v *Vegas) GameOver(final int) {
+	if v.Cumulative {
+		v.bankroll = final
+	}
+}
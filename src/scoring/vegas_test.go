@@ -0,0 +1,73 @@
+package scoring
+
+import (
+	"testing"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+func TestVegasStep(t *testing.T) {
+	tests := []struct {
+		name string
+		step model.Step
+		want int
+	}{
+		{"waste to tableau", wasteToTableau, 0},
+		{"tableau to foundation", tableauToFound, VEGAS_CARD_VALUE},
+		{"foundation to tableau", foundToTableau, -VEGAS_CARD_VALUE},
+		{"turning a card over", flippingMove, 0},
+		{"recycle", recycle, 0},
+	}
+	scorer := &Vegas{}
+	for _, tt := range tests {
+		if got := scorer.Step(tt.step, drawOneState) + scorer.Recycle(drawOneState); got != tt.want {
+			t.Errorf("%s scores %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVegasPasses(t *testing.T) {
+	scorer := &Vegas{}
+	if got := scorer.Passes(drawOneState.Options); got != 1 {
+		t.Errorf("drawing one allows %d passes, want 1", got)
+	}
+	if got := scorer.Passes(drawThreeState.Options); got != 3 {
+		t.Errorf("drawing three allows %d passes, want 3", got)
+	}
+}
+
+func TestVegasBuyIn(t *testing.T) {
+	tests := []struct {
+		scorer *Vegas
+		// second is the score the second game starts with, after the first
+		// ended with $10 won back
+		second int
+	}{
+		{&Vegas{}, -VEGAS_BUY_IN},
+		{&Vegas{Cumulative: true}, 10 - 2*VEGAS_BUY_IN},
+	}
+	for _, tt := range tests {
+		tally := NewTally(tt.scorer)
+		if got := tally.Points(0); got != -VEGAS_BUY_IN {
+			t.Errorf("%s starts at %d, want %d", tt.scorer.Name(), got, -VEGAS_BUY_IN)
+		}
+		tally.Apply(tableauToFound, drawOneState)
+		tally.Apply(tableauToFound, drawOneState)
+		tt.scorer.GameOver(tally.Points(0))
+		if got := tt.scorer.Start(); got != tt.second {
+			t.Errorf("%s starts the next game at %d, want %d", tt.scorer.Name(), got, tt.second)
+		}
+	}
+}
+
+func TestVegasSetBankroll(t *testing.T) {
+	scorer := &Vegas{Cumulative: true}
+	scorer.SetBankroll(100)
+	if got, want := scorer.Start(), 100-VEGAS_BUY_IN; got != want {
+		t.Errorf("starts at %d, want %d", got, want)
+	}
+	scorer.GameOver(-30)
+	if got := scorer.Bankroll(); got != -30 {
+		t.Errorf("bankroll is %d, want -30", got)
+	}
+}
@@ -0,0 +1,59 @@
+package solver
+
+import (
+	"slices"
+	"strings"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+const FACE_UP_BIT = 0x40
+const PILE_SEPARATOR = 0xff
+
+func cardByte(card model.Card) byte {
+	suitIndex := slices.Index(model.Suits, card.Suit)
+	b := byte(suitIndex*13 + int(card.Number))
+	if card.FaceUp {
+		b |= FACE_UP_BIT
+	}
+	return b
+}
+
+func appendPile(key []byte, pile *model.Pile) []byte {
+	for _, card := range pile.Cards {
+		key = append(key, cardByte(card))
+	}
+	return append(key, PILE_SEPARATOR)
+}
+
+// stateKey encodes a state so that positions which only differ by the order
+// of the tableau columns or foundations share a key.
+func stateKey(state *model.State) string {
+	key := []byte{}
+	key = appendPile(key, &state.Stock)
+	key = appendPile(key, &state.Waste)
+
+	// With a limit on passes, positions with fewer passes left are worse off
+	if state.StockRules().Passes > 0 {
+		key = append(key, byte(state.Recycles), PILE_SEPARATOR)
+	}
+
+	// Foundations are only identified by their top card
+	tops := []byte{}
+	for _, pile := range state.Foundations {
+		if top, ok := pile.Top(); ok {
+			tops = append(tops, cardByte(top))
+		}
+	}
+	slices.Sort(tops)
+	key = append(key, tops...)
+	key = append(key, PILE_SEPARATOR)
+
+	// Columns are interchangeable, so sort them
+	columns := make([]string, len(state.Tableau))
+	for i := range state.Tableau {
+		columns[i] = string(appendPile(nil, &state.Tableau[i]))
+	}
+	slices.Sort(columns)
+	return string(key) + strings.Join(columns, "")
+}
@@ -0,0 +1,329 @@
+package solver
+
+import (
+	"sort"
+	"time"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+type Status int
+
+const (
+	// Unknown means the search ran out of budget, or could not prove anything
+	// without peeking at face-down cards.
+	Unknown Status = iota
+	Solvable
+	Unsolvable
+)
+
+var statusNames = map[Status]string{
+	Unknown:    "unknown",
+	Solvable:   "solvable",
+	Unsolvable: "unsolvable",
+}
+
+func (s Status) String() string {
+	return statusNames[s]
+}
+
+type Options struct {
+	// Thoughtful lets the solver see face-down tableau cards. Without it the
+	// solver plays like a person would: it may backtrack freely, but once it
+	// turns a card over it is committed to that line of play.
+	Thoughtful bool
+	// MaxNodes and MaxDuration bound the search; zero means no limit.
+	MaxNodes    int
+	MaxDuration time.Duration
+}
+
+var DefaultOptions = Options{
+	Thoughtful:  true,
+	MaxNodes:    2_000_000,
+	MaxDuration: 10 * time.Second,
+}
+
+type Result struct {
+	Status Status
+	// Moves is the winning line of play when the status is Solvable.
+	Moves    []model.Move
+	Nodes    int
+	Duration time.Duration
+}
+
+type search struct {
+	opts     Options
+	state    *model.State
+	deadline time.Time
+	done     <-chan struct{}
+	seen     map[string]struct{}
+	path     []model.Move
+	nodes    int
+	// outOfBudget is set once the node or time limit is hit, or the search
+	// is cancelled
+	outOfBudget bool
+}
+
+// outcome is the result of searching below a single state.
+type outcome int
+
+const (
+	lost outcome = iota
+	won
+	// committed means a card was turned over on a losing line, so the search
+	// is not allowed to try anything else.
+	committed
+	aborted
+)
+
+// Solve searches for a winning line of play from the given state. The state
+// itself is not modified. Only Klondike is supported; other games are
+// reported as Unknown. Closing done cancels the search, which then reports
+// Unknown; a nil done never cancels.
+func Solve(state *model.State, opts Options, done <-chan struct{}) Result {
+	start := time.Now()
+	if state.Options.Game != model.GameKlondike {
+		return Result{Status: Unknown}
+	}
+	s := &search{
+		opts:  opts,
+		state: state.Clone(),
+		done:  done,
+		seen:  map[string]struct{}{},
+	}
+	if opts.MaxDuration > 0 {
+		s.deadline = start.Add(opts.MaxDuration)
+	}
+
+	result := Result{}
+	switch s.solve() {
+	case won:
+		result.Status = Solvable
+		result.Moves = append([]model.Move{}, s.path...)
+	case lost:
+		result.Status = Unsolvable
+	default:
+		result.Status = Unknown
+	}
+	result.Nodes = s.nodes
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (s *search) solve() outcome {
+	if s.state.IsWon() {
+		return won
+	}
+
+	// Check the budget, looking at the clock and for cancellation only
+	// every so often
+	s.nodes++
+	if s.opts.MaxNodes > 0 && s.nodes > s.opts.MaxNodes {
+		s.outOfBudget = true
+	}
+	if s.nodes%1024 == 0 {
+		if !s.deadline.IsZero() && time.Now().After(s.deadline) {
+			s.outOfBudget = true
+		}
+		select {
+		case <-s.done:
+			s.outOfBudget = true
+		default:
+		}
+	}
+	if s.outOfBudget {
+		return aborted
+	}
+
+	// Don't search the same position twice
+	key := stateKey(s.state)
+	if _, ok := s.seen[key]; ok {
+		return lost
+	}
+	s.seen[key] = struct{}{}
+
+	for _, moves := range s.candidates() {
+		steps, ok := s.applyAll(moves)
+		if !ok {
+			continue
+		}
+		s.path = append(s.path, moves...)
+		result := s.solve()
+		if result == won {
+			return won
+		}
+		s.path = s.path[:len(s.path)-len(moves)]
+		s.revertAll(steps)
+
+		if result == aborted || result == committed {
+			return result
+		}
+		if !s.opts.Thoughtful && steps[len(steps)-1].Flipped {
+			return committed
+		}
+	}
+	return lost
+}
+
+func (s *search) applyAll(moves []model.Move) ([]model.Step, bool) {
+	steps := make([]model.Step, 0, len(moves))
+	for _, move := range moves {
+		step, err := s.state.Apply(move)
+		if err != nil {
+			s.revertAll(steps)
+			return nil, false
+		}
+		steps = append(steps, step)
+	}
+	return steps, true
+}
+
+func (s *search) revertAll(steps []model.Step) {
+	for i := len(steps) - 1; i >= 0; i-- {
+		s.state.Revert(steps[i])
+	}
+}
+
+// candidate is a sequence of moves the search treats as a single step.
+type candidate struct {
+	moves []model.Move
+	rank  int
+}
+
+// candidates lists the move sequences worth trying from the current state,
+// most promising first. Only moves that can never matter are left out, so an
+// exhausted search really does mean the game cannot be won.
+func (s *search) candidates() [][]model.Move {
+	// A card that no other card could ever need is always played home straight away
+	if move, ok := s.safeFoundationMove(); ok {
+		return [][]model.Move{{move}}
+	}
+
+	candidates := s.stockCandidates()
+	for _, move := range s.state.LegalMoves() {
+		if move.Kind != model.MoveCards || s.isPointless(move) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			moves: []model.Move{move},
+			rank:  s.state.RankMove(move),
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].rank > candidates[j].rank
+	})
+
+	sequences := make([][]model.Move, len(candidates))
+	for i, c := range candidates {
+		sequences[i] = c.moves
+	}
+	return sequences
+}
+
+// stockCandidates turns the stock over, recycling it if allowed, until it
+// is back where it started. Every time a card that can be played turns up
+// on the waste, the draws followed by that play become a candidate. Drawing
+// never changes anything else, so this covers every useful use of the stock
+// while keeping the stock position out of the search.
+func (s *search) stockCandidates() []candidate {
+	candidates := []candidate{}
+	draws := []model.Move{}
+	steps := []model.Step{}
+	startStock := s.state.Stock.Len()
+	recycled := false
+	limit := 2 * (s.state.Stock.Len() + s.state.Waste.Len() + 1)
+
+	for len(draws) < limit {
+		move := model.DrawMove()
+		if s.state.Stock.IsEmpty() {
+			move = model.RecycleMove()
+			recycled = true
+		}
+		step, err := s.state.Apply(move)
+		if err != nil {
+			break
+		}
+		draws = append(draws, move)
+		steps = append(steps, step)
+		if recycled && s.state.Stock.Len() == startStock {
+			break
+		}
+
+		// Offer every place the new waste card can go
+		if top, ok := s.state.Waste.Top(); ok {
+			for _, to := range s.state.PileIDs() {
+				if to.Kind != model.PileFoundation && to.Kind != model.PileTableau {
+					continue
+				}
+				if !s.state.CanAccept(to, []model.Card{top}) {
+					continue
+				}
+				play := model.CardsMove(model.WasteID, to, 1)
+				candidates = append(candidates, candidate{
+					moves: append(append([]model.Move{}, draws...), play),
+					rank:  s.state.RankMove(play) - len(draws),
+				})
+			}
+		}
+	}
+
+	s.revertAll(steps)
+	return candidates
+}
+
+// isPointless reports moves that lead to an equivalent position, such as
+// moving a whole column into another empty column.
+func (s *search) isPointless(move model.Move) bool {
+	if move.Kind != model.MoveCards {
+		return false
+	}
+	if move.From.Kind == model.PileFoundation && move.To.Kind == model.PileFoundation {
+		return true
+	}
+	return move.From.Kind == model.PileTableau &&
+		move.To.Kind == model.PileTableau &&
+		move.Count == s.state.Pile(move.From).Len() &&
+		s.state.Pile(move.To).IsEmpty()
+}
+
+// safeFoundationMove finds a card that can be played home without ever
+// needing it back. A card is only ever needed in the tableau to hold the
+// next lower card of the opposite color, so it is safe once both of those
+// are home already.
+func (s *search) safeFoundationMove() (model.Move, bool) {
+	homeCount := map[model.Suit]model.Number{}
+	for _, pile := range s.state.Foundations {
+		if top, ok := pile.Top(); ok {
+			homeCount[top.Suit] = top.Number
+		}
+	}
+
+	for _, from := range s.state.PileIDs() {
+		if from.Kind != model.PileWaste && from.Kind != model.PileTableau {
+			continue
+		}
+		top, ok := s.state.Pile(from).Top()
+		if !ok || !top.FaceUp {
+			continue
+		}
+		safe := top.Number <= model.Two
+		if !safe {
+			safe = true
+			for _, suit := range model.Suits {
+				if suit.IsOppositeColor(top.Suit) && homeCount[suit] < top.Number-1 {
+					safe = false
+				}
+			}
+		}
+		if !safe {
+			continue
+		}
+		for i := range s.state.Foundations {
+			move := model.CardsMove(from, model.FoundationID(i), 1)
+			if s.state.CanAccept(move.To, []model.Card{top}) {
+				return move, true
+			}
+		}
+	}
+	return model.Move{}, false
+}
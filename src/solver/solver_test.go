@@ -0,0 +1,78 @@
+package solver
+
+import (
+	"testing"
+
+	"urffer.xyz/go-solitaire/src/model"
+)
+
+func TestSolveReplaysToWin(t *testing.T) {
+	for _, seed := range []uint64{4, 5} {
+		result := Solve(model.NewGame(seed, model.DefaultOptions), DefaultOptions, nil)
+		if result.Status != Solvable {
+			t.Fatalf("seed %d is %s, want solvable", seed, result.Status)
+		}
+		state := model.NewGame(seed, model.DefaultOptions)
+		for i, move := range result.Moves {
+			if _, err := state.Apply(move); err != nil {
+				t.Fatalf("seed %d move %d (%s): %v", seed, i+1, move, err)
+			}
+		}
+		if !state.IsWon() {
+			t.Errorf("seed %d: the winning line of play does not win", seed)
+		}
+	}
+}
+
+func TestSolveUnsolvable(t *testing.T) {
+	// The two of hearts covers its own ace and has nowhere to go
+	state := model.NewState(model.DefaultOptions)
+	state.Tableau[0].Cards = []model.Card{
+		{Number: model.Ace, Suit: model.Heart},
+		{Number: model.Two, Suit: model.Heart, FaceUp: true},
+	}
+	state.Tableau[1].Cards = []model.Card{
+		{Number: model.Four, Suit: model.Spade, FaceUp: true},
+	}
+	result := Solve(state, DefaultOptions, nil)
+	if result.Status != Unsolvable {
+		t.Errorf("status is %s, want unsolvable", result.Status)
+	}
+}
+
+func TestSolveOutOfBudget(t *testing.T) {
+	opts := DefaultOptions
+	opts.MaxNodes = 10
+	result := Solve(model.NewGame(4, model.DefaultOptions), opts, nil)
+	if result.Status != Unknown {
+		t.Errorf("status is %s, want unknown", result.Status)
+	}
+	if result.Nodes > opts.MaxNodes+1 {
+		t.Errorf("searched %d nodes with a budget of %d", result.Nodes, opts.MaxNodes)
+	}
+}
+
+func TestSolveCancelled(t *testing.T) {
+	done := make(chan struct{})
+	close(done)
+	result := Solve(model.NewGame(1, model.DefaultOptions), DefaultOptions, done)
+	if result.Status != Unknown {
+		t.Errorf("status is %s, want unknown", result.Status)
+	}
+}
+
+func TestStateKeyIgnoresColumnOrder(t *testing.T) {
+	state := model.NewGame(1, model.DefaultOptions)
+	swapped := state.Clone()
+	swapped.Tableau[0], swapped.Tableau[6] = swapped.Tableau[6], swapped.Tableau[0]
+	if stateKey(state) != stateKey(swapped) {
+		t.Errorf("swapping two columns changed the key")
+	}
+
+	if _, err := swapped.Apply(model.DrawMove()); err != nil {
+		t.Fatal(err)
+	}
+	if stateKey(state) == stateKey(swapped) {
+		t.Errorf("drawing a card left the key unchanged")
+	}
+}
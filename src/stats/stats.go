@@ -0,0 +1,122 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+)
+
+// STATS_VERSION is bumped whenever the layout of the stats file changes.
+const STATS_VERSION = 1
+const STATS_FILE_NAME = "stats.json"
+
+// Record holds the statistics of one kind of game.
+type Record struct {
+	Played        int `json:"played"`
+	Won           int `json:"won"`
+	CurrentStreak int `json:"currentStreak"`
+	BestStreak    int `json:"bestStreak"`
+	// The bests are only meaningful once at least one game has been won
+	FastestWin  time.Duration `json:"fastestWin"`
+	FewestMoves int           `json:"fewestMoves"`
+	BestScore   int           `json:"bestScore"`
+}
+
+func (r *Record) WinRate() float64 {
+	if r.Played == 0 {
+		return 0
+	}
+	return float64(r.Won) / float64(r.Played)
+}
+
+// Store keeps the records of every kind of game and persists them to disk.
+type Store struct {
+	Version int                `json:"version"`
+	Records map[string]*Record `json:"records"`
+	// Bankroll is the money won in cumulative Vegas scoring, kept for the
+	// next session
+	Bankroll int `json:"bankroll"`
+
+	path string
+}
+
+// Load reads the stats file at path, starting afresh if there isn't one yet.
+func Load(path string) (*Store, error) {
+	s := &Store{
+		Version: STATS_VERSION,
+		Records: map[string]*Record{},
+		path:    path,
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Version != STATS_VERSION {
+		return nil, fmt.Errorf("unsupported stats version %d", s.Version)
+	}
+	if s.Records == nil {
+		s.Records = map[string]*Record{}
+	}
+	return s, nil
+}
+
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+func (s *Store) record(category string) *Record {
+	r, ok := s.Records[category]
+	if !ok {
+		r = &Record{}
+		s.Records[category] = r
+	}
+	return r
+}
+
+func (s *Store) RecordWin(category string, elapsed time.Duration, moves int, score int) {
+	r := s.record(category)
+	if r.Won == 0 {
+		r.FastestWin, r.FewestMoves, r.BestScore = elapsed, moves, score
+	} else {
+		r.FastestWin = min(r.FastestWin, elapsed)
+		r.FewestMoves = min(r.FewestMoves, moves)
+		r.BestScore = max(r.BestScore, score)
+	}
+	r.Played++
+	r.Won++
+	r.CurrentStreak++
+	r.BestStreak = max(r.BestStreak, r.CurrentStreak)
+}
+
+func (s *Store) RecordLoss(category string) {
+	r := s.record(category)
+	r.Played++
+	r.CurrentStreak = 0
+}
+
+func (s *Store) Reset() {
+	s.Records = map[string]*Record{}
+}
+
+// Categories lists the kinds of game that have been played, in name order.
+func (s *Store) Categories() []string {
+	categories := make([]string, 0, len(s.Records))
+	for category := range s.Records {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"urffer.xyz/go-solitaire/src/game"
+	"urffer.xyz/go-solitaire/src/util"
+)
+
+// TOUCH_DRAG_SLOP is how far a finger moves before a touch becomes a drag.
+const TOUCH_DRAG_SLOP = 10
+
+// LONG_PRESS_TICKS is how long a finger rests in place to ask for a hint.
+const LONG_PRESS_TICKS = 45
+
+// TOUCH_MOUSE_GRACE_TICKS is how long the mouse is ignored after a touch, as
+// some systems make up mouse clicks from touches.
+const TOUCH_MOUSE_GRACE_TICKS = 30
+
+// touchTracker follows the finger that came down first. Other fingers are
+// ignored until it is lifted.
+type touchTracker struct {
+	id       ebiten.TouchID
+	active   bool
+	startPos util.Pos[int]
+	pos      util.Pos[int]
+
+	startTick int
+	endTick   int
+
+	dragging    bool
+	longPressed bool
+}
+
+// updateTouch plays the game by touch: dragging moves cards, a tap sends a
+// card to the best place it can go, and a long press shows a hint. It
+// returns true while touch input is in use, when the mouse should be left
+// alone.
+func (g *Game) updateTouch() bool {
+	t := &g.touch
+	if !t.active {
+		ids := inpututil.AppendJustPressedTouchIDs(nil)
+		if len(ids) == 0 {
+			return t.endTick > 0 && g.ticks-t.endTick < TOUCH_MOUSE_GRACE_TICKS
+		}
+		t.id, t.active, t.dragging, t.longPressed = ids[0], true, false, false
+		t.startPos = util.MakePosFromTuple(ebiten.TouchPosition(t.id))
+		t.pos = t.startPos
+		t.startTick = g.ticks
+		game.SetTouchInput(true)
+		g.board.SetCusrorPos(t.pos)
+		return true
+	}
+
+	if inpututil.IsTouchJustReleased(t.id) {
+		t.active = false
+		t.endTick = g.ticks
+		switch {
+		case t.dragging:
+			g.board.SetCusrorPos(t.pos)
+			g.board.MouseUp()
+		case !t.longPressed:
+			// A tap where there is no card to move acts as a click, e.g. on the draw pile
+			g.board.SetCusrorPos(t.startPos)
+			if !g.board.AutoMove() {
+				g.board.MouseDown()
+				g.board.MouseUp()
+			}
+		}
+		return true
+	}
+
+	t.pos = util.MakePosFromTuple(ebiten.TouchPosition(t.id))
+	moved := !util.Pos[float64]{}.AlmostEq(t.pos.Sub(t.startPos).ToFloatPos(), TOUCH_DRAG_SLOP*g.deviceScale)
+	switch {
+	case t.dragging:
+		g.board.SetCusrorPos(t.pos)
+	case t.longPressed:
+	case moved:
+		// Pick the cards up from where the finger came down, then follow it
+		t.dragging = true
+		g.board.SetCusrorPos(t.startPos)
+		g.board.MouseDown()
+		g.board.SetCusrorPos(t.pos)
+	case g.ticks-t.startTick >= LONG_PRESS_TICKS:
+		t.longPressed = true
+		g.board.NextHint()
+	}
+	return true
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const CONFIG_DIR_NAME = "go-solitaire"
+
+// ConfigPath returns the path of a file in the game's directory inside the
+// user's config directory, creating any directories on the way if needed.
+func ConfigPath(name string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(configDir, CONFIG_DIR_NAME, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
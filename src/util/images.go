@@ -8,6 +8,7 @@ func ScaleEbitenImage(image *ebiten.Image, dims Dims) *ebiten.Image {
 	xRatio := float64(bounds.X) / float64(dims.X)
 	yRatio := float64(bounds.Y) / float64(dims.Y)
 	ops.GeoM.Scale(1/xRatio, 1/yRatio)
+	ops.Filter = ebiten.FilterLinear
 
 	newImage := ebiten.NewImage(dims.X, dims.Y)
 	newImage.DrawImage(image, ops)